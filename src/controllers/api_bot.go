@@ -12,7 +12,15 @@ import (
 	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
 )
 
-// ReceiveAPIHandler renders route GET "/{version}/bot/{token}/receive"
+/*
+<summary>
+	ReceiveAPIHandler renders route GET "/{version}/bot/{token}/receive", newest first
+
+	Url parameters: ?timestamp={unix} lower bound, ?until={unix} upper bound
+	Url parameters: ?chatid= ?type={text|image|...} ?fromme={bool} ?frominternal={bool} ?trackid= ?search={text}
+	Url parameters: ?limit= page size, up to 1000, ?cursor= from "next" of the previous page
+</summary>
+*/
 func ReceiveAPIHandler(w http.ResponseWriter, r *http.Request) {
 	response := &models.QpReceiveResponse{}
 
@@ -34,9 +42,7 @@ func ReceiveAPIHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	queryValues := r.URL.Query()
-	paramTimestamp := queryValues.Get("timestamp")
-	timestamp, err := GetTimestamp(paramTimestamp)
+	filter, err := models.ParseReceiveFilter(r.URL.Query())
 	if err != nil {
 		metrics.MessageReceiveErrors.Inc()
 		response.ParseError(err)
@@ -46,14 +52,30 @@ func ReceiveAPIHandler(w http.ResponseWriter, r *http.Request) {
 
 	response.Total = uint64(server.Handler.GetTotal())
 
-	messages := GetMessages(server, timestamp)
+	found, err := server.FindMessages(filter)
+	if err != nil {
+		metrics.MessageReceiveErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	messages, next, err := filter.Apply(found)
+	if err != nil {
+		metrics.MessageReceiveErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
 	metrics.MessagesReceived.Add(float64(len(messages)))
 
 	response.Bot = *server.Bot
 	response.Messages = messages
+	response.Next = next
 
-	if timestamp > 0 {
-		response.ParseSuccess(fmt.Sprintf("getting with timestamp: %v", timestamp))
+	if !filter.After.IsZero() {
+		response.ParseSuccess(fmt.Sprintf("getting with timestamp: %v", filter.After.Unix()))
 	} else {
 		response.ParseSuccess("getting without filter")
 	}
@@ -112,6 +134,12 @@ func SendAny(w http.ResponseWriter, r *http.Request) {
 		request.TrackId = trackid
 	}
 
+	// override inreply if passed throw any other way
+	inreply := GetInReply(r)
+	if len(inreply) > 0 {
+		request.InReply = inreply
+	}
+
 	if len(request.Url) > 0 {
 		// base 64 content to byte array
 		err = request.GenerateUrlContent()
@@ -122,7 +150,7 @@ func SendAny(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		SendDocument(server, response, &request.QpSendRequest, w)
+		SendDocument(server, response, &request.QpSendRequest, w, r)
 	} else if len(request.Content) > 0 {
 		// base 64 content to byte array
 		err = request.GenerateEmbbedContent()
@@ -133,7 +161,7 @@ func SendAny(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		SendDocument(server, response, &request.QpSendRequest, w)
+		SendDocument(server, response, &request.QpSendRequest, w, r)
 	} else {
 		// text msg
 
@@ -145,7 +173,7 @@ func SendAny(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		Send(server, response, &request.QpSendRequest, w, nil)
+		Send(server, response, &request.QpSendRequest, w, r, nil)
 	}
 }
 
@@ -196,7 +224,165 @@ func SendText(w http.ResponseWriter, r *http.Request) {
 	if len(trackid) > 0 {
 		request.TrackId = trackid
 	}
-	Send(server, response, request, w, nil)
+
+	// override inreply if passed throw any other way
+	inreply := GetInReply(r)
+	if len(inreply) > 0 {
+		request.InReply = inreply
+	}
+	Send(server, response, request, w, r, nil)
+}
+
+/*
+<summary>
+	Renders route POST "/{version}/bot/{token}/sendlocation/{chatid}"
+
+	Sends a native location pin, body: latitude, longitude, (optional) name, address, url
+	Chat id, at this order of priority
+	Path parameters: {chatid}
+	Url parameters: ?chatid={chatId}
+	Header parameters: X-QUEPASA-CHATID = {chatId}
+	Body parameters: chatId
+</summary>
+*/
+func SendLocation(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpSendResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// Declare a new request struct.
+	request := &models.QpSendRequestLocation{}
+
+	// Try to decode the request body into the struct. If there is an error,
+	// respond to the client with the error message and a 400 status code.
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// Getting ChatId parameter
+	err = request.EnsureValidChatId(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// override trackid if passed throw any other way
+	trackid := GetTrackId(r)
+	if len(trackid) > 0 {
+		request.TrackId = trackid
+	}
+
+	// override inreply if passed throw any other way
+	inreply := GetInReply(r)
+	if len(inreply) > 0 {
+		request.InReply = inreply
+	}
+
+	waMsg, err := request.ToWhatsappMessage()
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	SendMessage(server, response, waMsg, w, r)
+}
+
+/*
+<summary>
+	Renders route POST "/{version}/bot/{token}/sendcontact/{chatid}"
+
+	Sends contact cards, body: contact or contacts, each with name and phones or a raw vcard
+	Chat id, at this order of priority
+	Path parameters: {chatid}
+	Url parameters: ?chatid={chatId}
+	Header parameters: X-QUEPASA-CHATID = {chatId}
+	Body parameters: chatId
+</summary>
+*/
+func SendContact(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpSendResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// Declare a new request struct.
+	request := &models.QpSendRequestContact{}
+
+	// Try to decode the request body into the struct. If there is an error,
+	// respond to the client with the error message and a 400 status code.
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// Getting ChatId parameter
+	err = request.EnsureValidChatId(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// override trackid if passed throw any other way
+	trackid := GetTrackId(r)
+	if len(trackid) > 0 {
+		request.TrackId = trackid
+	}
+
+	// override inreply if passed throw any other way
+	inreply := GetInReply(r)
+	if len(inreply) > 0 {
+		request.InReply = inreply
+	}
+
+	waMsg, err := request.ToWhatsappMessage()
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	SendMessage(server, response, waMsg, w, r)
 }
 
 /*
@@ -210,9 +396,11 @@ func SendText(w http.ResponseWriter, r *http.Request) {
 	Url parameters: ?chatid={chatId}
 	Url parameters: ?fileName={fileName}
 	Url parameters: ?text={text} only images
+	Url parameters: ?inreply={messageId}
 	Header parameters: X-QUEPASA-CHATID = {chatId}
 	Header parameters: X-QUEPASA-FILENAME = {fileName}
 	Header parameters: X-QUEPASA-TEXT = {text} only images
+	Header parameters: X-QUEPASA-INREPLY = {messageId}
 </summary>
 */
 func SendDocumentFromBinary(w http.ResponseWriter, r *http.Request) {
@@ -275,7 +463,13 @@ func SendDocumentFromBinary(w http.ResponseWriter, r *http.Request) {
 		request.TrackId = trackid
 	}
 
-	SendDocument(server, response, request, w)
+	// override inreply if passed throw any other way
+	inreply := GetInReply(r)
+	if len(inreply) > 0 {
+		request.InReply = inreply
+	}
+
+	SendDocument(server, response, request, w, r)
 }
 
 /*
@@ -286,6 +480,7 @@ func SendDocumentFromBinary(w http.ResponseWriter, r *http.Request) {
 	Body parameter: {fileName}
 	Body parameter: {text} only images
 	Body parameter: {content}
+	Body parameter: {inReply}
 </summary>
 */
 func SendDocumentFromEncoded(w http.ResponseWriter, r *http.Request) {
@@ -335,7 +530,13 @@ func SendDocumentFromEncoded(w http.ResponseWriter, r *http.Request) {
 	if len(trackid) > 0 {
 		request.TrackId = trackid
 	}
-	SendDocument(server, response, &request.QpSendRequest, w)
+
+	// override inreply if passed throw any other way
+	inreply := GetInReply(r)
+	if len(inreply) > 0 {
+		request.InReply = inreply
+	}
+	SendDocument(server, response, &request.QpSendRequest, w, r)
 }
 
 /*
@@ -346,6 +547,7 @@ func SendDocumentFromEncoded(w http.ResponseWriter, r *http.Request) {
 	Body parameter: {chatId}
 	Body parameter: {fileName}
 	Body parameter: {text} only images
+	Body parameter: {inReply}
 </summary>
 */
 func SendDocumentFromUrl(w http.ResponseWriter, r *http.Request) {
@@ -396,10 +598,16 @@ func SendDocumentFromUrl(w http.ResponseWriter, r *http.Request) {
 		request.TrackId = trackid
 	}
 
-	SendDocument(server, response, &request.QpSendRequest, w)
+	// override inreply if passed throw any other way
+	inreply := GetInReply(r)
+	if len(inreply) > 0 {
+		request.InReply = inreply
+	}
+
+	SendDocument(server, response, &request.QpSendRequest, w, r)
 }
 
-func Send(server *models.QPWhatsappServer, response *models.QpSendResponse, request *models.QpSendRequest, w http.ResponseWriter, attach *whatsapp.WhatsappAttachment) {
+func Send(server *models.QPWhatsappServer, response *models.QpSendResponse, request *models.QpSendRequest, w http.ResponseWriter, r *http.Request, attach *whatsapp.WhatsappAttachment) {
 	waMsg, err := request.ToWhatsappMessage()
 	if err != nil {
 		metrics.MessageSendErrors.Inc()
@@ -416,6 +624,39 @@ func Send(server *models.QPWhatsappServer, response *models.QpSendResponse, requ
 		waMsg.Type = whatsapp.TextMessageType
 	}
 
+	SendMessage(server, response, waMsg, w, r)
+}
+
+// Dispatch an already formatted message and respond with its result
+func SendMessage(server *models.QPWhatsappServer, response *models.QpSendResponse, waMsg *whatsapp.WhatsappMessage, w http.ResponseWriter, r *http.Request) {
+
+	// override typing delay if passed throw any other way
+	typingdelay := GetTypingDelay(r)
+	if typingdelay > 0 {
+		waMsg.TypingDelay = typingdelay
+	}
+
+	err := whatsapp.ValidateTypingDelay(waMsg.TypingDelay)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// storing for delivery at a future time, responds with the job id
+	schedule := GetSchedule(r)
+	if len(schedule) > 0 {
+		ScheduleMessage(server, response, waMsg, w, schedule, GetTimezone(r))
+		return
+	}
+
+	// storing for later delivery, responds with the job id
+	if GetQueue(r) {
+		EnqueueMessage(server, response, waMsg, w)
+		return
+	}
+
 	sendResponse, err := server.SendMessage(waMsg)
 	if err != nil {
 		metrics.MessageSendErrors.Inc()
@@ -437,7 +678,58 @@ func Send(server *models.QPWhatsappServer, response *models.QpSendResponse, requ
 	RespondInterface(w, response)
 }
 
-func SendDocument(server *models.QPWhatsappServer, response *models.QpSendResponse, request *models.QpSendRequest, w http.ResponseWriter) {
+// Stores a message on the durable queue of the server
+func EnqueueMessage(server *models.QPWhatsappServer, response *models.QpSendResponse, waMsg *whatsapp.WhatsappMessage, w http.ResponseWriter) {
+	item, err := server.Queue.Enqueue(waMsg)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	result := &models.QpSendResponseMessage{}
+	result.Wid = server.GetWid()
+	result.Id = item.MessageId
+	result.ChatId = item.ChatId
+	result.TrackId = item.TrackId
+	result.JobId = item.ID
+
+	response.ParseQueued(result)
+	RespondInterface(w, response)
+}
+
+// Stores a message on the durable queue of the server, for delivery at a future time
+func ScheduleMessage(server *models.QPWhatsappServer, response *models.QpSendResponse, waMsg *whatsapp.WhatsappMessage, w http.ResponseWriter, schedule string, timezone string) {
+	at, err := models.ParseScheduleTime(schedule, timezone)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	item, err := server.Queue.Schedule(waMsg, at)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	result := &models.QpSendResponseMessage{}
+	result.Wid = server.GetWid()
+	result.Id = item.MessageId
+	result.ChatId = item.ChatId
+	result.TrackId = item.TrackId
+	result.JobId = item.ID
+	result.Schedule = &item.Next
+
+	response.ParseScheduled(result)
+	RespondInterface(w, response)
+}
+
+func SendDocument(server *models.QPWhatsappServer, response *models.QpSendResponse, request *models.QpSendRequest, w http.ResponseWriter, r *http.Request) {
 	attach, err := request.ToWhatsappAttachment()
 	if err != nil {
 		metrics.MessageSendErrors.Inc()
@@ -446,5 +738,5 @@ func SendDocument(server *models.QPWhatsappServer, response *models.QpSendRespon
 		return
 	}
 
-	Send(server, response, request, w, attach)
+	Send(server, response, request, w, r, attach)
 }
@@ -58,6 +58,112 @@ func GetTrackId(r *http.Request) (result string) {
 	return
 }
 
+/*
+<summary>
+	Find a message id to reply to, quoting it
+	Getting from PATH => QUERY => HEADER
+</summary>
+*/
+func GetInReply(r *http.Request) (result string) {
+
+	// retrieve from url path parameter
+	result = chi.URLParam(r, "inreply")
+	if len(result) == 0 {
+
+		// retrieve from url query parameter
+		if r.URL.Query().Has("inreply") {
+			result = r.URL.Query().Get("inreply")
+		} else {
+
+			// retrieve from header parameter
+			result = r.Header.Get("X-QUEPASA-INREPLY")
+		}
+	}
+	return
+}
+
+/*
+<summary>
+	Indicates that the message should be stored on the durable queue, delivered asynchronously
+	Getting from QUERY => HEADER
+</summary>
+*/
+func GetQueue(r *http.Request) bool {
+	var result string
+
+	// retrieve from url query parameter
+	if r.URL.Query().Has("queue") {
+		result = r.URL.Query().Get("queue")
+	} else {
+
+		// retrieve from header parameter
+		result = r.Header.Get("X-QUEPASA-QUEUE")
+	}
+
+	value, _ := strconv.ParseBool(result)
+	return value
+}
+
+/*
+<summary>
+	Find a time to deliver the message, scheduling it
+	Getting from QUERY => HEADER
+</summary>
+*/
+func GetSchedule(r *http.Request) (result string) {
+
+	// retrieve from url query parameter
+	if r.URL.Query().Has("schedule") {
+		result = r.URL.Query().Get("schedule")
+	} else {
+
+		// retrieve from header parameter
+		result = r.Header.Get("X-QUEPASA-SCHEDULE")
+	}
+	return
+}
+
+/*
+<summary>
+	Find an IANA timezone to interpret local schedule times, ex: America/Sao_Paulo
+	Getting from QUERY => HEADER
+</summary>
+*/
+func GetTimezone(r *http.Request) (result string) {
+
+	// retrieve from url query parameter
+	if r.URL.Query().Has("timezone") {
+		result = r.URL.Query().Get("timezone")
+	} else {
+
+		// retrieve from header parameter
+		result = r.Header.Get("X-QUEPASA-TIMEZONE")
+	}
+	return
+}
+
+/*
+<summary>
+	Find max milliseconds showing typing indicator before sending
+	Getting from QUERY => HEADER
+</summary>
+*/
+func GetTypingDelay(r *http.Request) uint {
+	var result string
+
+	// retrieve from url query parameter
+	if r.URL.Query().Has("typingdelay") {
+		result = r.URL.Query().Get("typingdelay")
+	} else {
+
+		// retrieve from header parameter
+		result = r.Header.Get("X-QUEPASA-TYPINGDELAY")
+	}
+
+	value, _ := strconv.ParseUint(result, 10, 32)
+	return uint(value)
+}
+
 // Getting PictureId from PATH => QUERY => HEADER
 func GetPictureId(r *http.Request) (result string) {
 
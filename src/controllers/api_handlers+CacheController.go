@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+//region CONTROLLER - CACHE
+
+/*
+<summary>
+	Renders route GET "/{version}/cache" => current limits of in memory messages
+	Renders route POST "/{version}/cache" => updates limits of in memory messages
+
+	Body parameter: {maxentries} max messages kept in memory
+	Body parameter: {maxage} duration (ex: 12h), older messages by its timestamp are evicted
+	Body parameter: {maxbytes} max estimated memory, including attachments
+	Zero values follows the environment, CACHEMAXENTRIES, CACHEMAXAGE and CACHEMAXBYTES
+</summary>
+*/
+func CacheController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpCacheResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		request := &models.QpCacheRequest{}
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			jsonErr := fmt.Errorf("invalid json body: %s", err.Error())
+			response.ParseError(jsonErr)
+			RespondInterface(w, response)
+			return
+		}
+
+		entries := server.Bot.CacheMaxEntries
+		if request.MaxEntries != nil {
+			entries = *request.MaxEntries
+		}
+
+		age := server.Bot.CacheMaxAge
+		if request.MaxAge != nil {
+			age, err = request.GetMaxAgeSeconds()
+			if err != nil {
+				response.ParseError(err)
+				RespondInterface(w, response)
+				return
+			}
+		}
+
+		bytes := server.Bot.CacheMaxBytes
+		if request.MaxBytes != nil {
+			bytes = *request.MaxBytes
+		}
+
+		err = server.UpdateCachePolicy(entries, age, bytes)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	response.ParsePolicy(server.CachePolicy())
+	response.ParseSuccess(fmt.Sprintf("cache max entries: %v, max age: %s, max bytes: %v", response.MaxEntries, response.MaxAge, response.MaxBytes))
+	RespondInterface(w, response)
+}
+
+//endregion
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+//region CONTROLLER - CALLS
+
+/*
+<summary>
+	Renders route GET "/{version}/calls" => current settings of incoming calls
+	Renders route POST "/{version}/calls" => updates settings of incoming calls
+
+	Body parameter: {reject} automatically reject incoming calls
+	Body parameter: {reply} text sent to callers after reject, empty for none
+</summary>
+*/
+func CallsController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpCallsResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		request := &models.QpCallsRequest{}
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			jsonErr := fmt.Errorf("invalid json body: %s", err.Error())
+			response.ParseError(jsonErr)
+			RespondInterface(w, response)
+			return
+		}
+
+		if request.Reject != nil && *request.Reject != server.RejectCalls() {
+			err = server.ToggleRejectCalls()
+			if err != nil {
+				response.ParseError(err)
+				RespondInterface(w, response)
+				return
+			}
+		}
+
+		if request.Reply != nil && *request.Reply != server.CallReply() {
+			err = server.UpdateCallReply(*request.Reply)
+			if err != nil {
+				response.ParseError(err)
+				RespondInterface(w, response)
+				return
+			}
+		}
+	}
+
+	response.Reject = server.RejectCalls()
+	response.Reply = server.CallReply()
+	response.ParseSuccess(fmt.Sprintf("reject calls: %v", response.Reject))
+	RespondInterface(w, response)
+}
+
+//endregion
@@ -0,0 +1,251 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+//region CONTROLLER - CAMPAIGN
+
+/*
+<summary>
+	Renders route POST "/{version}/campaign" => creates and starts a campaign
+
+	Json body: {name}, {text} with {{placeholders}}, {url} or {content} attachment, {pacing} seconds between recipients
+	Json body: {recipients} list of {chatId, variables}
+
+	Or multipart form with the same fields
+	Form file: recipients => csv with header, chatid|phone|number column, others as variables
+	Form file: attachment => optional
+</summary>
+*/
+func CreateCampaignController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpCampaignResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request, recipients, err := GetCampaignRequest(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// override trackid if passed throw any other way
+	trackid := GetTrackId(r)
+	if len(trackid) > 0 {
+		request.TrackId = trackid
+	}
+
+	waMsg, err := request.ToWhatsappMessage()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	campaign, err := server.Campaigns.Create(request.Name, request.GetPacing(), waMsg, recipients)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Campaign = campaign
+	response.ParseSuccess(fmt.Sprintf("campaign created with %v recipients", campaign.GetTotal()))
+	RespondInterface(w, response)
+}
+
+/*
+<summary>
+	Renders route GET "/{version}/campaign/{campaignid}" => single campaign with its recipients
+	Renders route GET "/{version}/campaign" => all campaigns of this bot
+
+	Path parameters: {campaignid}
+	Url parameters: ?status={queued|sent|failed|invalid|delivered|read} filter recipients on single
+	Url parameters: ?status={running|paused|finished|canceled} filter campaigns on list
+</summary>
+*/
+func CampaignController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpCampaignResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	campaignid := chi.URLParam(r, "campaignid")
+	if len(campaignid) > 0 {
+		campaign, err := server.Campaigns.Find(campaignid)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		if campaign == nil {
+			err = fmt.Errorf("campaign not found: %s", campaignid)
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		recipients, err := server.Campaigns.FindRecipients(campaignid, models.QpCampaignRecipientStatus(status))
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Campaign = campaign
+		response.Recipients = recipients
+		response.Total = uint(len(recipients))
+		response.ParseSuccess(string(campaign.Status))
+		RespondInterface(w, response)
+		return
+	}
+
+	campaigns, err := server.Campaigns.FindAll(models.QpCampaignStatus(status))
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Campaigns = campaigns
+	response.Total = uint(len(campaigns))
+	response.ParseSuccess(fmt.Sprintf("%v campaigns", response.Total))
+	RespondInterface(w, response)
+}
+
+/*
+<summary>
+	Renders route POST "/{version}/campaign/{campaignid}/pause" => stops sending until resumed
+	Renders route POST "/{version}/campaign/{campaignid}/resume" => continues sending, failed recipients are sent again
+	Renders route DELETE "/{version}/campaign/{campaignid}" => cancels remaining recipients
+
+	Path parameters: {campaignid}
+</summary>
+*/
+func CampaignActionController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpCampaignResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	action := chi.URLParam(r, "action")
+	if r.Method == http.MethodDelete {
+		action = "cancel"
+	}
+
+	response.Campaign, err = ApplyCampaignAction(server, chi.URLParam(r, "campaignid"), action)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess(string(response.Campaign.Status))
+	RespondInterface(w, response)
+}
+
+//endregion
+
+// Pause, resume or cancel a campaign of the server
+func ApplyCampaignAction(server *models.QPWhatsappServer, id string, action string) (*models.QpCampaign, error) {
+	switch strings.ToLower(action) {
+	case "pause":
+		return server.Campaigns.Pause(id)
+	case "resume":
+		return server.Campaigns.Resume(id)
+	case "cancel":
+		return server.Campaigns.Cancel(id)
+	default:
+		return nil, fmt.Errorf("invalid campaign action: %s", action)
+	}
+}
+
+// Reads a campaign request from json body or multipart form with csv
+func GetCampaignRequest(r *http.Request) (request *models.QpCampaignRequest, recipients []*models.QpCampaignRecipient, err error) {
+	request = &models.QpCampaignRequest{}
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			err = fmt.Errorf("invalid json body: %s", err.Error())
+			return
+		}
+
+		recipients = request.GetRecipients()
+		return
+	}
+
+	err = r.ParseMultipartForm(32 << 20)
+	if err != nil {
+		return
+	}
+
+	request.Name = r.FormValue("name")
+	request.Text = r.FormValue("text")
+	request.Url = r.FormValue("url")
+	request.TrackId = r.FormValue("trackId")
+	request.FileName = r.FormValue("fileName")
+
+	if pacing := r.FormValue("pacing"); len(pacing) > 0 {
+		value, err := strconv.ParseUint(pacing, 10, 32)
+		if err != nil {
+			return request, recipients, fmt.Errorf("invalid pacing: %s", pacing)
+		}
+		request.Pacing = uint(value)
+	}
+
+	file, _, err := r.FormFile("recipients")
+	if err != nil {
+		err = fmt.Errorf("recipients csv file missing: %s", err)
+		return
+	}
+	defer file.Close()
+
+	recipients, err = models.ParseCampaignRecipientsCSV(file)
+	if err != nil {
+		return
+	}
+
+	attachment, header, err := r.FormFile("attachment")
+	if err != nil {
+		if err == http.ErrMissingFile {
+			err = nil
+		}
+		return
+	}
+	defer attachment.Close()
+
+	request.QpSendRequest.Content, err = io.ReadAll(attachment)
+	if err != nil {
+		return
+	}
+
+	if len(request.FileName) == 0 {
+		request.FileName = header.Filename
+	}
+	return
+}
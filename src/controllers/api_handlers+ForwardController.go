@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	metrics "github.com/sufficit/sufficit-quepasa/metrics"
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+//region CONTROLLER - FORWARD
+
+/*
+<summary>
+	Renders route POST "/{version}/forward/{chatid}"
+
+	Body parameter: {messageId} cached message to forward
+	Chat id of destination, at this order of priority
+	Path parameters: {chatid}
+	Url parameters: ?chatid={chatId}
+	Header parameters: X-QUEPASA-CHATID = {chatId}
+	Body parameters: chatId
+</summary>
+*/
+func ForwardController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpSendResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// Declare a new request struct.
+	request := &models.QpForwardRequest{}
+
+	// Try to decode the request body into the struct. If there is an error,
+	// respond to the client with the error message and a 400 status code.
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		jsonErr := fmt.Errorf("invalid json body: %s", err.Error())
+		response.ParseError(jsonErr)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = request.Validate()
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// Getting ChatId parameter
+	err = request.EnsureValidChatId(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	waMsg, err := server.GetForwardMessage(request.MessageId, request.ChatId)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// override trackid if passed throw any other way
+	waMsg.TrackId = request.TrackId
+	trackid := GetTrackId(r)
+	if len(trackid) > 0 {
+		waMsg.TrackId = trackid
+	}
+
+	SendMessage(server, response, waMsg, w, r)
+}
+
+//endregion
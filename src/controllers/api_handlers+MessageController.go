@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	metrics "github.com/sufficit/sufficit-quepasa/metrics"
+	models "github.com/sufficit/sufficit-quepasa/models"
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+//region CONTROLLER - MESSAGE
+
+/*
+<summary>
+	Renders route DELETE "/{version}/message/{messageId}" => revoke, delete for everyone
+	Renders route PATCH "/{version}/message/{messageId}" => edit text
+
+	Path parameters: {messageId}
+	Body parameter: {text} only on edit
+</summary>
+*/
+func MessageController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpSendResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	messageId := chi.URLParam(r, "messageId")
+	if len(messageId) == 0 {
+		metrics.MessageSendErrors.Inc()
+		err = fmt.Errorf("empty message id")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	var sendResponse whatsapp.IWhatsappSendResponse
+	switch r.Method {
+	case http.MethodPatch:
+		request := &models.QpEditRequest{}
+		err = json.NewDecoder(r.Body).Decode(request)
+		if err != nil {
+			metrics.MessageSendErrors.Inc()
+			jsonErr := fmt.Errorf("invalid json body: %s", err.Error())
+			response.ParseError(jsonErr)
+			RespondInterface(w, response)
+			return
+		}
+
+		sendResponse, err = server.EditMessage(messageId, request.Text)
+	default:
+		sendResponse, err = server.RevokeMessage(messageId)
+	}
+
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	result := &models.QpSendResponseMessage{}
+	result.Wid = server.GetWid()
+	result.Id = sendResponse.GetID()
+
+	response.ParseSuccess(result)
+	RespondInterface(w, response)
+}
+
+//endregion
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+//region CONTROLLER - PRESENCE
+
+/*
+<summary>
+	Renders route POST "/{version}/chatpresence/{chatid}" => typing indicators on a chat
+
+	Body parameter: {presence} composing, recording or paused
+	Chat id, at this order of priority
+	Path parameters: {chatid}
+	Url parameters: ?chatid={chatId}
+	Header parameters: X-QUEPASA-CHATID = {chatId}
+	Body parameters: chatId
+</summary>
+*/
+func ChatPresenceController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpPresenceRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		jsonErr := fmt.Errorf("invalid json body: %s", err.Error())
+		response.ParseError(jsonErr)
+		RespondInterface(w, response)
+		return
+	}
+
+	chatId := models.GetChatId(r)
+	if len(chatId) == 0 {
+		chatId = request.ChatId
+	}
+
+	if len(chatId) == 0 {
+		err = fmt.Errorf("chat id missing")
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	presence, err := request.GetChatPresence()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = server.SendChatPresence(chatId, presence)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess(string(presence))
+	RespondInterface(w, response)
+}
+
+/*
+<summary>
+	Renders route POST "/{version}/presence" => account online or offline
+
+	Body parameter: {presence} available or unavailable
+</summary>
+*/
+func PresenceController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpPresenceRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		jsonErr := fmt.Errorf("invalid json body: %s", err.Error())
+		response.ParseError(jsonErr)
+		RespondInterface(w, response)
+		return
+	}
+
+	available, err := request.GetAvailable()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = server.SendPresence(available)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if available {
+		response.ParseSuccess("available")
+	} else {
+		response.ParseSuccess("unavailable")
+	}
+	RespondInterface(w, response)
+}
+
+//endregion
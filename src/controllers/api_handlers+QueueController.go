@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+//region CONTROLLER - QUEUE
+
+/*
+<summary>
+	Renders route GET "/{version}/queue/{jobid}" => single job
+	Renders route GET "/{version}/queue" => all jobs of this bot
+
+	Path parameters: {jobid}
+	Url parameters: ?status={pending|scheduled|sending|sent|failed|canceled} only on list
+</summary>
+*/
+func QueueController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpQueueResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	jobid := chi.URLParam(r, "jobid")
+	if len(jobid) > 0 {
+		item, err := server.Queue.Find(jobid)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		if item == nil {
+			err = fmt.Errorf("job not found: %s", jobid)
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Job = item
+		response.ParseSuccess(string(item.Status))
+		RespondInterface(w, response)
+		return
+	}
+
+	status := models.QpQueueStatus(r.URL.Query().Get("status"))
+	items, err := server.Queue.FindAll(status)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Jobs = items
+	response.Total = uint(len(items))
+	response.ParseSuccess(fmt.Sprintf("%v jobs", response.Total))
+	RespondInterface(w, response)
+}
+
+//endregion
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	metrics "github.com/sufficit/sufficit-quepasa/metrics"
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+//region CONTROLLER - REACT
+
+/*
+<summary>
+	Renders route POST "/{version}/react"
+
+	Body parameter: {messageId}
+	Body parameter: {reaction} emoji, empty to remove
+	Body parameter: {chatId} optional if message is on cache
+</summary>
+*/
+func ReactController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpSendResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// Declare a new request struct.
+	request := &models.QpReactionRequest{}
+
+	// Try to decode the request body into the struct. If there is an error,
+	// respond to the client with the error message and a 400 status code.
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		jsonErr := fmt.Errorf("invalid json body: %s", err.Error())
+		response.ParseError(jsonErr)
+		RespondInterface(w, response)
+		return
+	}
+
+	// getting chat from the reacted message if not passed
+	if len(request.ChatId) == 0 {
+		request.ChatId = models.GetChatId(r)
+	}
+
+	if len(request.ChatId) == 0 && len(request.MessageId) > 0 {
+		cached, err := server.Handler.GetMessage(request.MessageId)
+		if err == nil {
+			request.ChatId = cached.Chat.ID
+		}
+	}
+
+	// override trackid if passed throw any other way
+	trackid := GetTrackId(r)
+	if len(trackid) > 0 {
+		request.TrackId = trackid
+	}
+
+	waMsg, err := request.ToWhatsappMessage()
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	SendMessage(server, response, waMsg, w, r)
+}
+
+//endregion
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	models "github.com/sufficit/sufficit-quepasa/models"
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+//region CONTROLLER - READ
+
+/*
+<summary>
+	Renders route POST "/{version}/read/{chatid}" => marks received messages as read
+
+	Body parameter: {messageId} or {messageIds} list
+	Chat id optional if messages are on cache, at this order of priority
+	Path parameters: {chatid}
+	Url parameters: ?chatid={chatId}
+	Header parameters: X-QUEPASA-CHATID = {chatId}
+	Body parameters: chatId
+</summary>
+*/
+func ReadController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	request := &models.QpReadRequest{}
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		jsonErr := fmt.Errorf("invalid json body: %s", err.Error())
+		response.ParseError(jsonErr)
+		RespondInterface(w, response)
+		return
+	}
+
+	chatId := models.GetChatId(r)
+	if len(chatId) == 0 {
+		chatId = request.ChatId
+	}
+
+	if len(chatId) > 0 {
+		chatId, err = whatsapp.FormatEndpoint(chatId)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+	}
+
+	ids := request.GetMessageIds()
+	err = server.MarkRead(chatId, ids...)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.ParseSuccess(fmt.Sprintf("%v messages marked as read", len(ids)))
+	RespondInterface(w, response)
+}
+
+//endregion
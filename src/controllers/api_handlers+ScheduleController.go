@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+//region CONTROLLER - SCHEDULE
+
+/*
+<summary>
+	Renders route GET "/{version}/schedule" => scheduled messages not delivered yet
+	Renders route DELETE "/{version}/schedule/{jobid}" => cancel a scheduled message
+
+	Any send method accepts a schedule, at this order of priority
+	Url parameters: ?schedule={time}&timezone={IANA timezone}
+	Header parameters: X-QUEPASA-SCHEDULE = {time}, X-QUEPASA-TIMEZONE = {IANA timezone}
+	Time as unix timestamp, RFC3339 or yyyy-MM-ddTHH:mm:ss on timezone (UTC default)
+</summary>
+*/
+func ScheduleController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpQueueResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		jobid := chi.URLParam(r, "jobid")
+		item, err := server.Queue.Cancel(jobid)
+		if err != nil {
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		response.Job = item
+		response.ParseSuccess("canceled with success")
+		RespondInterface(w, response)
+		return
+	}
+
+	items, err := server.Queue.FindAll(models.QueueScheduled)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Jobs = items
+	response.Total = uint(len(items))
+	response.ParseSuccess(fmt.Sprintf("%v scheduled", response.Total))
+	RespondInterface(w, response)
+}
+
+//endregion
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	metrics "github.com/sufficit/sufficit-quepasa/metrics"
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+//region CONTROLLER - STATUS
+
+/*
+<summary>
+	Renders route POST "/{version}/status" => posts a status (story) from this bot
+
+	Body parameter: {text} status text or media caption
+	Body parameter: {url} or {content} base64, optional image or video
+	Body parameter: {backgroundColor}, {textColor} as #RRGGBB, {font} only for text status
+	Fonts: sans_serif, serif, norican_regular, bryndan_write, bebasneue_regular, oswald_heavy
+	Limitation: custom audience is not supported, posts follow the status privacy configured on the phone
+</summary>
+*/
+func StatusController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpSendResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// Declare a new request struct.
+	request := &models.QpStatusRequest{}
+
+	// Try to decode the request body into the struct. If there is an error,
+	// respond to the client with the error message and a 400 status code.
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		jsonErr := fmt.Errorf("invalid json body: %s", err.Error())
+		response.ParseError(jsonErr)
+		RespondInterface(w, response)
+		return
+	}
+
+	// override trackid if passed throw any other way
+	trackid := GetTrackId(r)
+	if len(trackid) > 0 {
+		request.TrackId = trackid
+	}
+
+	waMsg, err := request.ToWhatsappMessage()
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	SendMessage(server, response, waMsg, w, r)
+}
+
+//endregion
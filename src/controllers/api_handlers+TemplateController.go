@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	metrics "github.com/sufficit/sufficit-quepasa/metrics"
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+//region CONTROLLER - TEMPLATE
+
+/*
+<summary>
+	Renders route POST "/{version}/sendtemplate/{chatid}"
+
+	Body parameter: {template} id or name of a stored template
+	Body parameter: {variables} values for {{placeholders}} on text and url
+	Chat id, at this order of priority
+	Path parameters: {chatid}
+	Url parameters: ?chatid={chatId}
+	Header parameters: X-QUEPASA-CHATID = {chatId}
+	Body parameters: chatId
+</summary>
+*/
+func SendTemplateController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpSendResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// Declare a new request struct.
+	request := &models.QpSendTemplateRequest{}
+
+	// Try to decode the request body into the struct. If there is an error,
+	// respond to the client with the error message and a 400 status code.
+	err = json.NewDecoder(r.Body).Decode(request)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		jsonErr := fmt.Errorf("invalid json body: %s", err.Error())
+		response.ParseError(jsonErr)
+		RespondInterface(w, response)
+		return
+	}
+
+	// Getting ChatId parameter
+	err = request.EnsureValidChatId(r)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	// override trackid if passed throw any other way
+	trackid := GetTrackId(r)
+	if len(trackid) > 0 {
+		request.TrackId = trackid
+	}
+
+	// override inreply if passed throw any other way
+	inreply := GetInReply(r)
+	if len(inreply) > 0 {
+		request.InReply = inreply
+	}
+
+	template, err := server.GetTemplate(request.Template)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	err = request.Render(template)
+	if err != nil {
+		metrics.MessageSendErrors.Inc()
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	if len(request.Url) > 0 {
+		err = request.GenerateUrlContent()
+		if err != nil {
+			metrics.MessageSendErrors.Inc()
+			response.ParseError(err)
+			RespondInterface(w, response)
+			return
+		}
+
+		SendDocument(server, response, &request.QpSendRequest, w, r)
+		return
+	}
+
+	Send(server, response, &request.QpSendRequest, w, r, nil)
+}
+
+/*
+<summary>
+	Renders route GET "/{version}/templates" => templates available to this bot
+</summary>
+*/
+func TemplatesController(w http.ResponseWriter, r *http.Request) {
+	response := &models.QpTemplatesResponse{}
+
+	server, err := GetServer(r)
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	templates, err := server.GetTemplates()
+	if err != nil {
+		response.ParseError(err)
+		RespondInterface(w, response)
+		return
+	}
+
+	response.Templates = templates
+	response.Total = uint(len(templates))
+	response.ParseSuccess(fmt.Sprintf("%v templates", response.Total))
+	RespondInterface(w, response)
+}
+
+//endregion
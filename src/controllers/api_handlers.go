@@ -33,6 +33,10 @@ func RegisterAPIControllers(r chi.Router) {
 		r.Post(endpoint+"/send/{chatid}", SendAny)
 		r.Post(endpoint+"/sendtext", SendText)
 		r.Post(endpoint+"/sendtext/{chatid}", SendText)
+		r.Post(endpoint+"/sendlocation", SendLocation)
+		r.Post(endpoint+"/sendlocation/{chatid}", SendLocation)
+		r.Post(endpoint+"/sendcontact", SendContact)
+		r.Post(endpoint+"/sendcontact/{chatid}", SendContact)
 
 		// SENDING MSG ATTACH ---------------------
 
@@ -46,9 +50,69 @@ func RegisterAPIControllers(r chi.Router) {
 		r.Post(endpoint+"/sendbinary", SendDocumentFromBinary)
 		r.Post(endpoint+"/sendencoded", SendDocumentFromEncoded)
 
+		// reacting to a previous message, empty reaction to remove
+		r.Post(endpoint+"/react", ReactController)
+
+		// native polls, votes are received as messages
+		r.Post(endpoint+"/poll", PollController)
+		r.Post(endpoint+"/poll/{chatid}", PollController)
+
+		// stored templates, rendered with variables
+		r.Post(endpoint+"/sendtemplate", SendTemplateController)
+		r.Post(endpoint+"/sendtemplate/{chatid}", SendTemplateController)
+		r.Get(endpoint+"/templates", TemplatesController)
+
+		// re-sends a cached message to another chat
+		r.Post(endpoint+"/forward", ForwardController)
+		r.Post(endpoint+"/forward/{chatid}", ForwardController)
+
+		// status (stories), received as messages from status@broadcast
+		r.Post(endpoint+"/status", StatusController)
+
+		// presence, typing indicators and account availability
+		r.Post(endpoint+"/chatpresence", ChatPresenceController)
+		r.Post(endpoint+"/chatpresence/{chatid}", ChatPresenceController)
+		r.Post(endpoint+"/presence", PresenceController)
+
+		// read receipts
+		r.Post(endpoint+"/read", ReadController)
+		r.Post(endpoint+"/read/{chatid}", ReadController)
+
+		// incoming calls settings
+		r.Get(endpoint+"/calls", CallsController)
+		r.Post(endpoint+"/calls", CallsController)
+
+		// in memory messages limits
+		r.Get(endpoint+"/cache", CacheController)
+		r.Post(endpoint+"/cache", CacheController)
+
 		// ----------------------------------------
 		// SENDING MSG ----------------------------
 
+		// MESSAGE CONTROL ------------------------
+		// ----------------------------------------
+
+		r.Delete(endpoint+"/message/{messageId}", MessageController)
+		r.Patch(endpoint+"/message/{messageId}", MessageController)
+
+		// durable queue, messages sent with ?queue=true
+		r.Get(endpoint+"/queue", QueueController)
+		r.Get(endpoint+"/queue/{jobid}", QueueController)
+
+		// scheduled messages, sent with ?schedule={time}
+		r.Get(endpoint+"/schedule", ScheduleController)
+		r.Delete(endpoint+"/schedule/{jobid}", ScheduleController)
+
+		// bulk sending with pacing and per recipient status
+		r.Post(endpoint+"/campaign", CreateCampaignController)
+		r.Get(endpoint+"/campaign", CampaignController)
+		r.Get(endpoint+"/campaign/{campaignid}", CampaignController)
+		r.Delete(endpoint+"/campaign/{campaignid}", CampaignActionController)
+		r.Post(endpoint+"/campaign/{campaignid}/{action}", CampaignActionController)
+
+		// ----------------------------------------
+		// MESSAGE CONTROL ------------------------
+
 		r.Get(endpoint+"/receive", ReceiveAPIHandler)
 		r.Post(endpoint+"/attachment", AttachmentAPIHandlerV2)
 
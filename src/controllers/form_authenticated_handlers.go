@@ -36,11 +36,20 @@ func RegisterFormAuthenticatedControllers(r chi.Router) {
 	r.Post(FormEndpointPrefix+"/toggle", FormToggleController)
 	r.Post(FormEndpointPrefix+"/togglegroups", FormToggleGroupsController)
 	r.Post(FormEndpointPrefix+"/togglebroadcast", FormToggleBroadcastController)
+	r.Post(FormEndpointPrefix+"/toggleautoread", FormToggleAutoReadController)
+	r.Post(FormEndpointPrefix+"/togglerejectcalls", FormToggleRejectCallsController)
+
+	r.Get(FormTemplatesEndpoint, FormTemplatesController)
+	r.Post(FormTemplatesEndpoint, FormTemplateSaveController)
+	r.Post(FormTemplatesEndpoint+"/delete", FormTemplateDeleteController)
 
 	r.Get(FormEndpointPrefix+"/server/{id}", FormSendController)
 	r.Get(FormEndpointPrefix+"/server/{id}/send", FormSendController)
 	r.Post(FormEndpointPrefix+"/server/{id}/send", FormSendController)
 	r.Get(FormEndpointPrefix+"/server/{id}/receive", FormReceiveController)
+	r.Get(FormEndpointPrefix+"/server/{id}/campaigns", FormCampaignsController)
+	r.Get(FormEndpointPrefix+"/server/{id}/campaigns/{campaignid}", FormCampaignController)
+	r.Post(FormEndpointPrefix+"/server/{id}/campaigns/{campaignid}/{action}", FormCampaignActionController)
 }
 
 // Authentication manager on forms
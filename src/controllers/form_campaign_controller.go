@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+// FormCampaignsController renders route GET "/form/server/{id}/campaigns"
+func FormCampaignsController(w http.ResponseWriter, r *http.Request) {
+	data := models.QPFormCampaignData{PageTitle: "Campaigns", FormAccountEndpoint: FormAccountEndpoint}
+
+	server, err := GetServerForUserFromRequest(w, r)
+	if err != nil {
+		return
+	}
+
+	data.ServerID = server.ID()
+	data.Number = server.GetNumber()
+	data.Statuses = models.QpCampaignRecipientStatuses
+	data.Campaigns, err = server.Campaigns.FindAll("")
+	if err != nil {
+		data.ErrorMessage = err.Error()
+	}
+
+	renderCampaignForm(w, "views/bot/campaigns.tmpl", data)
+}
+
+// FormCampaignController renders route GET "/form/server/{id}/campaigns/{campaignid}" ?status={recipient status}
+func FormCampaignController(w http.ResponseWriter, r *http.Request) {
+	data := models.QPFormCampaignData{PageTitle: "Campaign", FormAccountEndpoint: FormAccountEndpoint}
+
+	server, err := GetServerForUserFromRequest(w, r)
+	if err != nil {
+		return
+	}
+
+	data.ServerID = server.ID()
+	data.Number = server.GetNumber()
+	data.Statuses = models.QpCampaignRecipientStatuses
+
+	campaignid := chi.URLParam(r, "campaignid")
+	data.Campaign, err = server.Campaigns.Find(campaignid)
+	if err == nil && data.Campaign == nil {
+		err = fmt.Errorf("campaign not found: %s", campaignid)
+	}
+
+	if err == nil {
+		status := models.QpCampaignRecipientStatus(r.URL.Query().Get("status"))
+		data.Recipients, err = server.Campaigns.FindRecipients(campaignid, status)
+	}
+
+	if err != nil {
+		data.ErrorMessage = err.Error()
+	}
+
+	renderCampaignForm(w, "views/bot/campaign.tmpl", data)
+}
+
+// FormCampaignActionController renders route POST "/form/server/{id}/campaigns/{campaignid}/{action}"
+func FormCampaignActionController(w http.ResponseWriter, r *http.Request) {
+	server, err := GetServerForUserFromRequest(w, r)
+	if err != nil {
+		return
+	}
+
+	campaignid := chi.URLParam(r, "campaignid")
+	_, err = ApplyCampaignAction(server, campaignid, chi.URLParam(r, "action"))
+	if err != nil {
+		RespondServerError(server, w, err)
+		return
+	}
+
+	http.Redirect(w, r, fmt.Sprintf("%s/server/%s/campaigns/%s", FormEndpointPrefix, server.ID(), campaignid), http.StatusFound)
+}
+
+func renderCampaignForm(w http.ResponseWriter, view string, data models.QPFormCampaignData) {
+	templates := template.Must(template.ParseFiles("views/layouts/main.tmpl", view))
+	templates.ExecuteTemplate(w, "main", data)
+}
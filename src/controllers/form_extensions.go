@@ -106,6 +106,38 @@ func FormToggleBroadcastController(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, FormAccountEndpoint, http.StatusFound)
 }
 
+func FormToggleAutoReadController(w http.ResponseWriter, r *http.Request) {
+	_, server, err := GetUserAndServer(w, r)
+	if err != nil {
+		// retorno já tratado pela funcao
+		return
+	}
+
+	err = server.ToggleAutoRead()
+	if err != nil {
+		RespondServerError(server, w, err)
+		return
+	}
+
+	http.Redirect(w, r, FormAccountEndpoint, http.StatusFound)
+}
+
+func FormToggleRejectCallsController(w http.ResponseWriter, r *http.Request) {
+	_, server, err := GetUserAndServer(w, r)
+	if err != nil {
+		// retorno já tratado pela funcao
+		return
+	}
+
+	err = server.ToggleRejectCalls()
+	if err != nil {
+		RespondServerError(server, w, err)
+		return
+	}
+
+	http.Redirect(w, r, FormAccountEndpoint, http.StatusFound)
+}
+
 func FormToggleGroupsController(w http.ResponseWriter, r *http.Request) {
 	_, server, err := GetUserAndServer(w, r)
 	if err != nil {
@@ -308,6 +340,22 @@ func GetServerFromRequest(r *http.Request) (*models.QPWhatsappServer, error) {
 	return models.GetServerFromID(wid)
 }
 
+// Server from url id, only if owned by the authenticated user, redirects or responds on error
+func GetServerForUserFromRequest(w http.ResponseWriter, r *http.Request) (server *models.QPWhatsappServer, err error) {
+	user, err := models.GetUser(r)
+	if err != nil {
+		RedirectToLogin(w, r)
+		return
+	}
+
+	server, ok := models.GetServersForUser(user)[chi.URLParam(r, "id")]
+	if !ok {
+		err = fmt.Errorf("server not found")
+		RespondErrorCode(w, err, http.StatusNotFound)
+	}
+	return
+}
+
 // Search for a server ID from an authenticated request
 func GetServerFromAuthenticatedRequest(user models.QPUser, r *http.Request) (server *models.QPWhatsappServer, err error) {
 	serverid := r.Form.Get("botID")
@@ -116,6 +116,7 @@ func uploadFile(w http.ResponseWriter, r *http.Request) (attach *WhatsappAttachm
 	attach.Mimetype = reader.Header.Get("content-type")
 	attach.FileLength = uint64(reader.Size)
 	attach.FileName = reader.Filename
+	attach.Ptt = IsVoiceMimetype(attach.Mimetype)
 	return
 }
 
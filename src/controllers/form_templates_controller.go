@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+
+	models "github.com/sufficit/sufficit-quepasa/models"
+)
+
+var FormTemplatesEndpoint string = FormEndpointPrefix + "/templates"
+
+// FormTemplatesController renders route GET "/form/templates" ?id={template id to edit}
+func FormTemplatesController(w http.ResponseWriter, r *http.Request) {
+	user, err := models.GetUser(r)
+	if err != nil {
+		RedirectToLogin(w, r)
+		return
+	}
+
+	data := models.QPFormTemplatesData{PageTitle: "Templates", User: user}
+	data.Servers = models.GetServersForUser(user)
+	data.Template = &models.QpTemplate{}
+
+	db := models.WhatsappService.DB.Template
+	id := r.URL.Query().Get("id")
+	if len(id) > 0 {
+		editing, err := db.Find(user.ID, id)
+		if err != nil {
+			data.ErrorMessage = err.Error()
+		} else if editing == nil {
+			data.ErrorMessage = fmt.Sprintf("template not found: %s", id)
+		} else {
+			data.Template = editing
+		}
+	}
+
+	data.Templates, err = db.FindAll(user.ID, "")
+	if err != nil {
+		data.ErrorMessage = err.Error()
+	}
+
+	renderTemplatesForm(w, data)
+}
+
+// FormTemplateSaveController renders route POST "/form/templates"
+func FormTemplateSaveController(w http.ResponseWriter, r *http.Request) {
+	user, err := models.GetUser(r)
+	if err != nil {
+		RedirectToLogin(w, r)
+		return
+	}
+
+	r.ParseForm()
+	db := models.WhatsappService.DB.Template
+
+	element := models.NewQpTemplate(user.ID)
+	id := r.Form.Get("id")
+	if len(id) > 0 {
+		element, err = db.Find(user.ID, id)
+		if err == nil && element == nil {
+			err = fmt.Errorf("template not found: %s", id)
+		}
+	}
+
+	if err == nil {
+		element.Context = r.Form.Get("context")
+		element.Name = r.Form.Get("name")
+		element.Text = r.Form.Get("text")
+		element.Url = r.Form.Get("url")
+		element.FileName = r.Form.Get("fileName")
+		element.Updated = time.Now().UTC()
+		err = element.Validate()
+	}
+
+	if err == nil && len(element.Context) > 0 {
+		if _, ok := models.GetServersForUser(user)[element.Context]; !ok {
+			err = fmt.Errorf("server not found: %s", element.Context)
+		}
+	}
+
+	if err == nil {
+		if len(id) > 0 {
+			err = db.Update(*element)
+		} else {
+			err = db.Add(*element)
+		}
+	}
+
+	if err != nil {
+		data := models.QPFormTemplatesData{PageTitle: "Templates", User: user, ErrorMessage: err.Error()}
+		data.Servers = models.GetServersForUser(user)
+		data.Templates, _ = db.FindAll(user.ID, "")
+		data.Template = element
+		renderTemplatesForm(w, data)
+		return
+	}
+
+	http.Redirect(w, r, FormTemplatesEndpoint, http.StatusFound)
+}
+
+// FormTemplateDeleteController renders route POST "/form/templates/delete"
+func FormTemplateDeleteController(w http.ResponseWriter, r *http.Request) {
+	user, err := models.GetUser(r)
+	if err != nil {
+		RedirectToLogin(w, r)
+		return
+	}
+
+	r.ParseForm()
+	err = models.WhatsappService.DB.Template.Delete(user.ID, r.Form.Get("id"))
+	if err != nil {
+		RespondErrorCode(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, FormTemplatesEndpoint, http.StatusFound)
+}
+
+func renderTemplatesForm(w http.ResponseWriter, data models.QPFormTemplatesData) {
+	templates := template.Must(template.ParseFiles("views/layouts/main.tmpl", "views/templates.tmpl"))
+	templates.ExecuteTemplate(w, "main", data)
+}
@@ -24,3 +24,18 @@ var MessageReceiveErrors = promauto.NewCounter(prometheus.CounterOpts{
 	Name: "quepasa_receive_message_errors_total",
 	Help: "Total message receive errors",
 })
+
+var CacheMessages = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "quepasa_cache_messages",
+	Help: "Messages on memory cache",
+}, []string{"wid"})
+
+var CacheBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "quepasa_cache_bytes",
+	Help: "Estimated memory of messages on cache",
+}, []string{"wid"})
+
+var CacheEvictions = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "quepasa_cache_evictions_total",
+	Help: "Total messages removed from cache by limits",
+})
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS queue (
+  `id` VARCHAR (100) PRIMARY KEY UNIQUE NOT NULL,
+  `context` VARCHAR (255) NOT NULL REFERENCES bots(id),
+  `chatid` VARCHAR (255) NOT NULL,
+  `messageid` VARCHAR (255) NOT NULL DEFAULT '',
+  `trackid` VARCHAR (100) NOT NULL DEFAULT '',
+  `status` VARCHAR (20) NOT NULL DEFAULT 'pending',
+  `attempts` INTEGER NOT NULL DEFAULT 0,
+  `error` TEXT NOT NULL DEFAULT '',
+  `payload` BLOB NOT NULL,
+  `created` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `next` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS queue_context_status ON queue (`context`, `status`);
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS campaigns (
+  `id` VARCHAR (100) PRIMARY KEY UNIQUE NOT NULL,
+  `context` VARCHAR (255) NOT NULL REFERENCES bots(id),
+  `name` VARCHAR (255) NOT NULL DEFAULT '',
+  `status` VARCHAR (20) NOT NULL DEFAULT 'running',
+  `pacing` INTEGER NOT NULL DEFAULT 0,
+  `payload` BLOB NOT NULL,
+  `created` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS campaigns_context_status ON campaigns (`context`, `status`);
+
+CREATE TABLE IF NOT EXISTS campaign_recipients (
+  `campaign` VARCHAR (100) NOT NULL REFERENCES campaigns(id),
+  `position` INTEGER NOT NULL DEFAULT 0,
+  `chatid` VARCHAR (255) NOT NULL,
+  `variables` TEXT NOT NULL DEFAULT '',
+  `status` VARCHAR (20) NOT NULL DEFAULT 'queued',
+  `messageid` VARCHAR (255) NOT NULL DEFAULT '',
+  `error` TEXT NOT NULL DEFAULT '',
+  `updated` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT campaign_recipients_pkey PRIMARY KEY (`campaign`, `chatid`)
+);
+
+CREATE INDEX IF NOT EXISTS campaign_recipients_status ON campaign_recipients (`campaign`, `status`);
+CREATE INDEX IF NOT EXISTS campaign_recipients_messageid ON campaign_recipients (`messageid`);
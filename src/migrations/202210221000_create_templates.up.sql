@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS templates (
+  `id` VARCHAR (100) PRIMARY KEY UNIQUE NOT NULL,
+  `user_id` VARCHAR (255) NOT NULL REFERENCES users(id),
+  `context` VARCHAR (255) NOT NULL DEFAULT '',
+  `name` VARCHAR (255) NOT NULL,
+  `text` TEXT NOT NULL DEFAULT '',
+  `url` TEXT NOT NULL DEFAULT '',
+  `filename` VARCHAR (255) NOT NULL DEFAULT '',
+  `created` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `updated` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT templates_name UNIQUE (`user_id`, `context`, `name`)
+);
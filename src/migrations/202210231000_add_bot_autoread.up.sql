@@ -0,0 +1 @@
+ ALTER TABLE bots ADD COLUMN autoread BOOLEAN NOT NULL DEFAULT FALSE;
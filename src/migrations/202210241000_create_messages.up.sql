@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS messages (
+  `context` VARCHAR (255) NOT NULL REFERENCES bots(id),
+  `id` VARCHAR (255) NOT NULL,
+  `chatid` VARCHAR (255) NOT NULL,
+  `trackid` VARCHAR (100) NOT NULL DEFAULT '',
+  `fromme` BOOLEAN NOT NULL DEFAULT FALSE,
+  `status` VARCHAR (20) NOT NULL DEFAULT '',
+  `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `payload` BLOB NOT NULL,
+  `content` BLOB DEFAULT NULL,
+  CONSTRAINT messages_pkey PRIMARY KEY (`context`, `id`)
+);
+
+CREATE INDEX IF NOT EXISTS messages_context_timestamp ON messages (`context`, `timestamp`);
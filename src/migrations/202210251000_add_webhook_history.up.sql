@@ -0,0 +1 @@
+ ALTER TABLE webhooks ADD COLUMN history BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+ ALTER TABLE bots ADD COLUMN rejectcalls BOOLEAN NOT NULL DEFAULT FALSE;
+ ALTER TABLE bots ADD COLUMN callreply TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ ALTER TABLE messages ADD COLUMN secret BLOB DEFAULT NULL;
@@ -0,0 +1,3 @@
+ ALTER TABLE bots ADD COLUMN cachemaxentries INTEGER NOT NULL DEFAULT 0;
+ ALTER TABLE bots ADD COLUMN cachemaxage INTEGER NOT NULL DEFAULT 0;
+ ALTER TABLE bots ADD COLUMN cachemaxbytes BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+ ALTER TABLE messages ADD COLUMN frominternal BOOLEAN NOT NULL DEFAULT FALSE;
+ ALTER TABLE messages ADD COLUMN type VARCHAR (20) NOT NULL DEFAULT '';
+ ALTER TABLE messages ADD COLUMN text TEXT NOT NULL DEFAULT '';
+ CREATE INDEX IF NOT EXISTS messages_context_chatid ON messages (`context`, `chatid`);
@@ -2,6 +2,7 @@ module github.com/sufficit/sufficit-quepasa/models
 
 require (
 	github.com/go-chi/chi/v5 v5.0.7
+	github.com/sufficit/sufficit-quepasa/metrics v0.0.0-00010101000000-000000000000
 	github.com/sufficit/sufficit-quepasa/whatsapp v0.0.0-00010101000000-000000000000
 	github.com/sufficit/sufficit-quepasa/whatsmeow v0.0.0-00010101000000-000000000000
 )
@@ -36,6 +37,8 @@ require (
 
 replace github.com/sufficit/sufficit-quepasa/library => ../library
 
+replace github.com/sufficit/sufficit-quepasa/metrics => ../metrics
+
 replace github.com/sufficit/sufficit-quepasa/whatsmeow => ../whatsmeow
 
 replace github.com/sufficit/sufficit-quepasa/whatsapp => ../whatsapp
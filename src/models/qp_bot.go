@@ -15,6 +15,12 @@ type QPBot struct {
 	Version         string `db:"version" json:"version,omitempty"`
 	HandleGroups    bool   `db:"handlegroups" json:"handlegroups,omitempty"`
 	HandleBroadcast bool   `db:"handlebroadcast" json:"handlebroadcast,omitempty"`
+	AutoRead        bool   `db:"autoread" json:"autoread,omitempty"`
+	RejectCalls     bool   `db:"rejectcalls" json:"rejectcalls,omitempty"`
+	CallReply       string `db:"callreply" json:"callreply,omitempty"`
+	CacheMaxEntries uint   `db:"cachemaxentries" json:"cachemaxentries,omitempty"`
+	CacheMaxAge     uint   `db:"cachemaxage" json:"cachemaxage,omitempty"` // seconds
+	CacheMaxBytes   uint64 `db:"cachemaxbytes" json:"cachemaxbytes,omitempty"`
 
 	db IQPBot
 }
@@ -92,6 +98,49 @@ func (bot *QPBot) UpdateBroadcast(value bool) (err error) {
 	return
 }
 
+func (bot *QPBot) UpdateAutoRead(value bool) (err error) {
+	err = bot.db.UpdateAutoRead(bot.ID, value)
+	if err != nil {
+		return
+	}
+
+	bot.AutoRead = value
+	return
+}
+
+func (bot *QPBot) UpdateRejectCalls(value bool) (err error) {
+	err = bot.db.UpdateRejectCalls(bot.ID, value)
+	if err != nil {
+		return
+	}
+
+	bot.RejectCalls = value
+	return
+}
+
+func (bot *QPBot) UpdateCallReply(value string) (err error) {
+	err = bot.db.UpdateCallReply(bot.ID, value)
+	if err != nil {
+		return
+	}
+
+	bot.CallReply = value
+	return
+}
+
+// Cache limits of this bot, zero values follows the environment
+func (bot *QPBot) UpdateCachePolicy(entries uint, age uint, bytes uint64) (err error) {
+	err = bot.db.UpdateCachePolicy(bot.ID, entries, age, bytes)
+	if err != nil {
+		return
+	}
+
+	bot.CacheMaxEntries = entries
+	bot.CacheMaxAge = age
+	bot.CacheMaxBytes = bytes
+	return
+}
+
 func (bot *QPBot) UpdateVerified(value bool) (err error) {
 	err = bot.db.UpdateVerified(bot.ID, value)
 	if err != nil {
@@ -124,6 +173,23 @@ func (bot *QPBot) UpdateToken(value string) (err error) {
 
 //endregion
 
+// Cache limits of this bot, falling back to the environment for each unset value
+func (bot *QPBot) GetCachePolicy() (policy QpCachePolicy) {
+	policy = ENV.CachePolicy()
+	if bot.CacheMaxEntries > 0 {
+		policy.MaxEntries = bot.CacheMaxEntries
+	}
+
+	if bot.CacheMaxAge > 0 {
+		policy.MaxAge = time.Duration(bot.CacheMaxAge) * time.Second
+	}
+
+	if bot.CacheMaxBytes > 0 {
+		policy.MaxBytes = bot.CacheMaxBytes
+	}
+	return
+}
+
 func (bot *QPBot) IsDevelopmentGlobal() bool {
 	return ENV.IsDevelopment()
 }
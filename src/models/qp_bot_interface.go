@@ -13,6 +13,10 @@ type IQPBot interface {
 	UpdateToken(id string, value string) error
 	UpdateGroups(id string, value bool) error
 	UpdateBroadcast(id string, value bool) error
+	UpdateAutoRead(id string, value bool) error
+	UpdateRejectCalls(id string, value bool) error
+	UpdateCallReply(id string, value string) error
+	UpdateCachePolicy(id string, entries uint, age uint, bytes uint64) error
 	UpdateVerified(id string, value bool) error
 	UpdateDevel(id string, value bool) error
 	UpdateVersion(id string, value string) error
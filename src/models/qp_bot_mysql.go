@@ -79,6 +79,10 @@ func (source QPBotMysql) Create(botID string, userID string) (QPBot, error) {
 UpdateToken(id string, value string) error
 UpdateGroups(id string, value bool) error
 UpdateBroadcast(id string, value bool) error
+UpdateAutoRead(id string, value bool) error
+UpdateRejectCalls(id string, value bool) error
+UpdateCallReply(id string, value string) error
+UpdateCachePolicy(id string, entries uint, age uint, bytes uint64) error
 UpdateVerified(id string, value bool) error
 UpdateDevel(id string, value bool) error
 UpdateVersion(id string, value string) error
@@ -105,6 +109,34 @@ func (source QPBotMysql) UpdateBroadcast(id string, value bool) error {
 	return err
 }
 
+func (source QPBotMysql) UpdateAutoRead(id string, value bool) error {
+	now := time.Now()
+	query := "UPDATE bots SET autoread = ?, updated_at = ? WHERE id = ?"
+	_, err := source.db.Exec(query, value, now, id)
+	return err
+}
+
+func (source QPBotMysql) UpdateRejectCalls(id string, value bool) error {
+	now := time.Now()
+	query := "UPDATE bots SET rejectcalls = ?, updated_at = ? WHERE id = ?"
+	_, err := source.db.Exec(query, value, now, id)
+	return err
+}
+
+func (source QPBotMysql) UpdateCallReply(id string, value string) error {
+	now := time.Now()
+	query := "UPDATE bots SET callreply = ?, updated_at = ? WHERE id = ?"
+	_, err := source.db.Exec(query, value, now, id)
+	return err
+}
+
+func (source QPBotMysql) UpdateCachePolicy(id string, entries uint, age uint, bytes uint64) error {
+	now := time.Now()
+	query := "UPDATE bots SET cachemaxentries = ?, cachemaxage = ?, cachemaxbytes = ?, updated_at = ? WHERE id = ?"
+	_, err := source.db.Exec(query, entries, age, bytes, now, id)
+	return err
+}
+
 func (source QPBotMysql) UpdateVerified(id string, value bool) error {
 	now := time.Now()
 	query := "UPDATE bots SET is_verified = ?, updated_at = ? WHERE id = ?"
@@ -74,6 +74,10 @@ func (source QPBotPostgres) Create(botID string, userID string) (QPBot, error) {
 UpdateToken(id string, value string) error
 UpdateGroups(id string, value bool) error
 UpdateBroadcast(id string, value bool) error
+UpdateAutoRead(id string, value bool) error
+UpdateRejectCalls(id string, value bool) error
+UpdateCallReply(id string, value string) error
+UpdateCachePolicy(id string, entries uint, age uint, bytes uint64) error
 UpdateVerified(id string, value bool) error
 UpdateDevel(id string, value bool) error
 UpdateVersion(id string, value string) error
@@ -100,6 +104,34 @@ func (source QPBotPostgres) UpdateBroadcast(id string, value bool) error {
 	return err
 }
 
+func (source QPBotPostgres) UpdateAutoRead(id string, value bool) error {
+	now := time.Now()
+	query := "UPDATE bots SET autoread = $1, updated_at = $2 WHERE id = $3"
+	_, err := source.db.Exec(query, value, now, id)
+	return err
+}
+
+func (source QPBotPostgres) UpdateRejectCalls(id string, value bool) error {
+	now := time.Now()
+	query := "UPDATE bots SET rejectcalls = $1, updated_at = $2 WHERE id = $3"
+	_, err := source.db.Exec(query, value, now, id)
+	return err
+}
+
+func (source QPBotPostgres) UpdateCallReply(id string, value string) error {
+	now := time.Now()
+	query := "UPDATE bots SET callreply = $1, updated_at = $2 WHERE id = $3"
+	_, err := source.db.Exec(query, value, now, id)
+	return err
+}
+
+func (source QPBotPostgres) UpdateCachePolicy(id string, entries uint, age uint, bytes uint64) error {
+	now := time.Now()
+	query := "UPDATE bots SET cachemaxentries = $1, cachemaxage = $2, cachemaxbytes = $3, updated_at = $4 WHERE id = $5"
+	_, err := source.db.Exec(query, entries, age, bytes, now, id)
+	return err
+}
+
 func (source QPBotPostgres) UpdateVerified(id string, value bool) error {
 	now := time.Now()
 	query := "UPDATE bots SET is_verified = $1, updated_at = $2 WHERE id = $3"
@@ -48,14 +48,14 @@ func (source QpBotWebhookSql) All() ([]*QpBotWebhook, error) {
 }
 
 func (source QpBotWebhookSql) Add(element QpBotWebhook) error {
-	query := `INSERT OR IGNORE INTO webhooks (context, url, forwardinternal, trackid, extra) VALUES (?, ?, ?, ?, ?)`
-	_, err := source.db.Exec(query, element.Context, element.Url, element.ForwardInternal, element.TrackId, element.GetExtraText())
+	query := `INSERT OR IGNORE INTO webhooks (context, url, forwardinternal, trackid, history, extra) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := source.db.Exec(query, element.Context, element.Url, element.ForwardInternal, element.TrackId, element.History, element.GetExtraText())
 	return err
 }
 
 func (source QpBotWebhookSql) Update(element QpBotWebhook) error {
-	query := `UPDATE webhooks SET forwardinternal = ?, trackid = ?, extra = ? WHERE context = ? AND url = ?`
-	_, err := source.db.Exec(query, element.ForwardInternal, element.TrackId, element.GetExtraText(), element.Context, element.Url)
+	query := `UPDATE webhooks SET forwardinternal = ?, trackid = ?, history = ?, extra = ? WHERE context = ? AND url = ?`
+	_, err := source.db.Exec(query, element.ForwardInternal, element.TrackId, element.History, element.GetExtraText(), element.Context, element.Url)
 	return err
 }
 
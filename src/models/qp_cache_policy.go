@@ -0,0 +1,61 @@
+package models
+
+import (
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Default limits of in memory messages, for each server
+const (
+	CacheDefaultMaxEntries        = 10000
+	CacheDefaultMaxAge            = 24 * time.Hour
+	CacheDefaultMaxBytes   uint64 = 64 << 20
+)
+
+// Limits of in memory messages of a single server, zero means unlimited
+type QpCachePolicy struct {
+	MaxEntries uint          `json:"maxentries"`
+	MaxAge     time.Duration `json:"maxage"`
+	MaxBytes   uint64        `json:"maxbytes"` // including attachment content
+}
+
+/*
+<summary>
+	Cache policy from environment, CACHEMAXENTRIES, CACHEMAXAGE (duration, ex: 24h) and CACHEMAXBYTES
+	Invalid values are ignored, keeping the defaults
+</summary>
+*/
+func (_ *Environment) CachePolicy() (policy QpCachePolicy) {
+	policy = QpCachePolicy{
+		MaxEntries: CacheDefaultMaxEntries,
+		MaxAge:     CacheDefaultMaxAge,
+		MaxBytes:   CacheDefaultMaxBytes,
+	}
+
+	if value, err := getenvStr("CACHEMAXENTRIES"); err == nil {
+		if entries, err := strconv.ParseUint(value, 10, 32); err == nil {
+			policy.MaxEntries = uint(entries)
+		} else {
+			log.Warnf("invalid cache max entries: %s, using default", value)
+		}
+	}
+
+	if value, err := getenvStr("CACHEMAXAGE"); err == nil {
+		if age, err := time.ParseDuration(value); err == nil {
+			policy.MaxAge = age
+		} else {
+			log.Warnf("invalid cache max age: %s, using default", value)
+		}
+	}
+
+	if value, err := getenvStr("CACHEMAXBYTES"); err == nil {
+		if bytes, err := strconv.ParseUint(value, 10, 64); err == nil {
+			policy.MaxBytes = bytes
+		} else {
+			log.Warnf("invalid cache max bytes: %s, using default", value)
+		}
+	}
+	return
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+/*
+<summary>
+	Request to update the limits of in memory messages of this bot
+	Omitted fields keep the current value, zero values follows the environment
+</summary>
+*/
+type QpCacheRequest struct {
+	MaxEntries *uint   `json:"maxentries,omitempty"`
+	MaxAge     *string `json:"maxage,omitempty"` // duration, ex: 12h
+	MaxBytes   *uint64 `json:"maxbytes,omitempty"`
+}
+
+// Max age in seconds, as stored for the bot, zero for empty values
+func (source *QpCacheRequest) GetMaxAgeSeconds() (seconds uint, err error) {
+	if source.MaxAge == nil || len(*source.MaxAge) == 0 {
+		return
+	}
+
+	age, err := time.ParseDuration(*source.MaxAge)
+	if err != nil || age < 0 {
+		err = fmt.Errorf("invalid max age: %s, use a duration like 12h", *source.MaxAge)
+		return
+	}
+
+	seconds = uint(age / time.Second)
+	return
+}
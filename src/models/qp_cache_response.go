@@ -0,0 +1,15 @@
+package models
+
+// Current limits of in memory messages for a bot, after falling back to the environment
+type QpCacheResponse struct {
+	QpResponse
+	MaxEntries uint   `json:"maxentries"`
+	MaxAge     string `json:"maxage"`
+	MaxBytes   uint64 `json:"maxbytes"`
+}
+
+func (source *QpCacheResponse) ParsePolicy(policy QpCachePolicy) {
+	source.MaxEntries = policy.MaxEntries
+	source.MaxAge = policy.MaxAge.String()
+	source.MaxBytes = policy.MaxBytes
+}
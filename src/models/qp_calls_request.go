@@ -0,0 +1,12 @@
+package models
+
+/*
+<summary>
+	Request to update how incoming calls are handled by this bot
+	Omitted fields keep the current value
+</summary>
+*/
+type QpCallsRequest struct {
+	Reject *bool   `json:"reject,omitempty"` // automatically reject incoming calls
+	Reply  *string `json:"reply,omitempty"`  // text sent to callers after reject, empty for none
+}
@@ -0,0 +1,8 @@
+package models
+
+// Current settings of incoming calls for a bot
+type QpCallsResponse struct {
+	QpResponse
+	Reject bool   `json:"reject"`
+	Reply  string `json:"reply,omitempty"`
+}
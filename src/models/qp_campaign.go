@@ -0,0 +1,119 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+type QpCampaignStatus string
+
+const (
+	// Sending to queued recipients
+	CampaignRunning QpCampaignStatus = "running"
+
+	// Stopped by user, can be resumed
+	CampaignPaused QpCampaignStatus = "paused"
+
+	// No more queued recipients
+	CampaignFinished QpCampaignStatus = "finished"
+
+	// Stopped by user, remaining recipients will not be sent
+	CampaignCanceled QpCampaignStatus = "canceled"
+)
+
+// Default wait time between each recipient
+const CampaignDefaultPacing = 5 * time.Second
+
+// Min wait time between each recipient, avoiding bans
+const CampaignMinPacing = 1 * time.Second
+
+// Same message sent to a list of recipients, stored on database
+type QpCampaign struct {
+	ID      string           `db:"id" json:"id"`
+	Context string           `db:"context" json:"wid"` // bot that will send this campaign
+	Name    string           `db:"name" json:"name,omitempty"`
+	Status  QpCampaignStatus `db:"status" json:"status"`
+	Pacing  uint             `db:"pacing" json:"pacing"` // seconds between each recipient
+	Payload []byte           `db:"payload" json:"-"`     // serialized message template with attachment content
+	Created time.Time        `db:"created" json:"created"`
+	Updated time.Time        `db:"updated" json:"updated"`
+
+	// Recipients count by status
+	Summary map[QpCampaignRecipientStatus]uint `db:"-" json:"summary,omitempty"`
+}
+
+func NewQpCampaign(context string, name string, pacing time.Duration, msg *whatsapp.WhatsappMessage) (campaign *QpCampaign, err error) {
+	content, err := MarshalQueuePayload(msg)
+	if err != nil {
+		return
+	}
+
+	if pacing == 0 {
+		pacing = CampaignDefaultPacing
+	} else if pacing < CampaignMinPacing {
+		pacing = CampaignMinPacing
+	}
+
+	now := time.Now().UTC()
+	campaign = &QpCampaign{
+		ID:      uuid.New().String(),
+		Context: context,
+		Name:    name,
+		Status:  CampaignRunning,
+		Pacing:  uint(pacing / time.Second),
+		Payload: content,
+		Created: now,
+		Updated: now,
+	}
+	return
+}
+
+func (source *QpCampaign) GetPacing() time.Duration {
+	return time.Duration(source.Pacing) * time.Second
+}
+
+// Total of recipients, from summary
+func (source *QpCampaign) GetTotal() (total uint) {
+	for _, count := range source.Summary {
+		total += count
+	}
+	return
+}
+
+// Recipients count of a status, from summary
+func (source *QpCampaign) GetCount(status QpCampaignRecipientStatus) uint {
+	return source.Summary[status]
+}
+
+// Indicates that still can be paused, resumed or canceled
+func (source *QpCampaign) IsActive() bool {
+	return source.Status == CampaignRunning || source.Status == CampaignPaused
+}
+
+// Renders the message template for a single recipient, placeholders without values are not sent
+func (source *QpCampaign) GetMessage(recipient *QpCampaignRecipient) (msg *whatsapp.WhatsappMessage, err error) {
+	msg, err = UnmarshalQueuePayload(source.Payload)
+	if err != nil {
+		return
+	}
+
+	text, missing := ReplaceVariables(msg.Text, recipient.Variables)
+	if len(missing) > 0 {
+		err = fmt.Errorf("missing variables: %s", strings.Join(missing, ", "))
+		return
+	}
+
+	msg.Id = recipient.MessageId
+	msg.Chat = whatsapp.WhatsappChat{ID: recipient.ChatId}
+	msg.Text = text
+
+	// following the campaign on webhooks
+	if len(msg.TrackId) == 0 {
+		msg.TrackId = source.ID
+	}
+	return
+}
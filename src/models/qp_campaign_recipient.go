@@ -0,0 +1,86 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+type QpCampaignRecipientStatus string
+
+const (
+	// Waiting for its turn
+	RecipientQueued QpCampaignRecipientStatus = "queued"
+
+	// Delivered to whatsapp servers
+	RecipientSent QpCampaignRecipientStatus = "sent"
+
+	// Error on sending, can be queued again by resuming the campaign
+	RecipientFailed QpCampaignRecipientStatus = "failed"
+
+	// Malformed number or not registered on whatsapp
+	RecipientInvalid QpCampaignRecipientStatus = "invalid"
+
+	// Received by the recipient device
+	RecipientDelivered QpCampaignRecipientStatus = "delivered"
+
+	// Viewed by the recipient
+	RecipientRead QpCampaignRecipientStatus = "read"
+)
+
+// All recipient status, in order of progress
+var QpCampaignRecipientStatuses = []QpCampaignRecipientStatus{
+	RecipientQueued,
+	RecipientInvalid,
+	RecipientFailed,
+	RecipientSent,
+	RecipientDelivered,
+	RecipientRead,
+}
+
+// Destination of a campaign and its current state
+type QpCampaignRecipient struct {
+	Campaign  string                    `db:"campaign" json:"-"`
+	Position  uint                      `db:"position" json:"position"` // order of sending
+	ChatId    string                    `db:"chatid" json:"chatid"`
+	Variables QpVariables               `db:"variables" json:"variables,omitempty"` // values for text placeholders
+	Status    QpCampaignRecipientStatus `db:"status" json:"status"`
+	MessageId string                    `db:"messageid" json:"messageid,omitempty"` // whatsapp message id, generated before sending
+	Error     string                    `db:"error" json:"error,omitempty"`
+	Updated   time.Time                 `db:"updated" json:"updated"`
+}
+
+// Recipient status of a message delivery state, empty if not tracked
+func GetCampaignRecipientStatus(status whatsapp.WhatsappMessageStatus) QpCampaignRecipientStatus {
+	switch status {
+	case whatsapp.MessageStatusDelivered:
+		return RecipientDelivered
+	case whatsapp.MessageStatusRead, whatsapp.MessageStatusPlayed:
+		return RecipientRead
+	default:
+		return ""
+	}
+}
+
+// Formatted chat id used to find duplicates, the trimmed original if not possible
+func (source *QpCampaignRecipient) GetNormalizedChatId() string {
+	chatid, err := whatsapp.FormatEndpoint(source.ChatId)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(source.ChatId))
+	}
+	return chatid
+}
+
+// Formats the chat id, marking as invalid if not possible
+func (source *QpCampaignRecipient) Validate() {
+	chatid, err := whatsapp.FormatEndpoint(source.ChatId)
+	if err != nil {
+		source.Status = RecipientInvalid
+		source.Error = err.Error()
+		return
+	}
+
+	source.ChatId = chatid
+	source.Status = RecipientQueued
+}
@@ -0,0 +1,164 @@
+package models
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+/*
+<summary>
+	Request to create a campaign, same message for each recipient
+	Text accepts {{placeholders}} filled with the variables of each recipient
+	Attachment as on any send request, Url or Content Base64
+</summary>
+*/
+type QpCampaignRequest struct {
+	QpSendAnyRequest
+	Name       string                       `json:"name,omitempty"`
+	Pacing     uint                         `json:"pacing,omitempty"` // seconds between each recipient
+	Recipients []QpCampaignRecipientRequest `json:"recipients"`
+}
+
+type QpCampaignRecipientRequest struct {
+	ChatId    string      `json:"chatId"`
+	Variables QpVariables `json:"variables,omitempty"`
+}
+
+func (source *QpCampaignRequest) GetPacing() time.Duration {
+	return time.Duration(source.Pacing) * time.Second
+}
+
+func (source *QpCampaignRequest) GetRecipients() (recipients []*QpCampaignRecipient) {
+	for _, recipient := range source.Recipients {
+		recipients = append(recipients, &QpCampaignRecipient{
+			ChatId:    recipient.ChatId,
+			Variables: recipient.Variables,
+		})
+	}
+	return
+}
+
+// Message template, without destination
+func (source *QpCampaignRequest) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage, err error) {
+	err = whatsapp.ValidateTypingDelay(source.TypingDelay)
+	if err != nil {
+		return
+	}
+
+	if len(source.Url) > 0 {
+		err = source.GenerateUrlContent()
+	} else if len(source.Content) > 0 {
+		err = source.GenerateEmbbedContent()
+	}
+
+	if err != nil {
+		return
+	}
+
+	msg = &whatsapp.WhatsappMessage{
+		TrackId:      source.TrackId,
+		Text:         source.Text,
+		TypingDelay:  source.TypingDelay,
+		Mentions:     source.Mentions,
+		MentionAll:   source.MentionAll,
+		FromMe:       true,
+		FromInternal: true,
+		Type:         whatsapp.TextMessageType,
+	}
+
+	if len(source.QpSendRequest.Content) > 0 {
+		attach, err := source.ToWhatsappAttachment()
+		if err != nil {
+			return msg, err
+		}
+
+		msg.Attachment = attach
+		msg.Type = whatsapp.GetMessageType(attach.Mimetype)
+	} else if len(msg.Text) == 0 {
+		err = fmt.Errorf("text not found, do not send empty messages")
+	}
+	return
+}
+
+// Columns accepted as recipient on csv files, otherwise the first one
+var QpCampaignRecipientColumns = []string{"chatid", "phone", "number"}
+
+/*
+<summary>
+	Reads recipients from a csv with header, separated by comma or semicolon
+	Every column, except the recipient, becomes a variable named as its header
+</summary>
+*/
+func ParseCampaignRecipientsCSV(reader io.Reader) (recipients []*QpCampaignRecipient, err error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return
+	}
+
+	parser := csv.NewReader(bytes.NewReader(content))
+	parser.FieldsPerRecord = -1
+	parser.TrimLeadingSpace = true
+
+	header := strings.SplitN(string(content), "\n", 2)[0]
+	if strings.Contains(header, ";") && !strings.Contains(header, ",") {
+		parser.Comma = ';'
+	}
+
+	records, err := parser.ReadAll()
+	if err != nil {
+		return
+	}
+
+	if len(records) < 2 {
+		err = fmt.Errorf("csv must have a header and at least one recipient")
+		return
+	}
+
+	columns := records[0]
+	for index := range columns {
+		columns[index] = strings.TrimSpace(strings.TrimPrefix(columns[index], "\ufeff"))
+	}
+
+	chatColumn := 0
+	for index, column := range columns {
+		if containsFold(QpCampaignRecipientColumns, column) {
+			chatColumn = index
+			break
+		}
+	}
+
+	for _, record := range records[1:] {
+		if len(record) <= chatColumn || len(strings.TrimSpace(record[chatColumn])) == 0 {
+			continue
+		}
+
+		recipient := &QpCampaignRecipient{ChatId: strings.TrimSpace(record[chatColumn])}
+		for index, value := range record {
+			if index == chatColumn || index >= len(columns) || len(columns[index]) == 0 {
+				continue
+			}
+
+			if recipient.Variables == nil {
+				recipient.Variables = QpVariables{}
+			}
+			recipient.Variables[columns[index]] = strings.TrimSpace(value)
+		}
+		recipients = append(recipients, recipient)
+	}
+	return
+}
+
+func containsFold(values []string, value string) bool {
+	for _, item := range values {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
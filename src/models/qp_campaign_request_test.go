@@ -0,0 +1,74 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCampaignRecipientsCSV(t *testing.T) {
+	cases := []struct {
+		name       string
+		content    string
+		recipients []QpCampaignRecipient
+		fails      bool
+	}{
+		{
+			name:    "comma with variables",
+			content: "phone,name\n5521999990001,Ana\n5521999990002,Bruno\n",
+			recipients: []QpCampaignRecipient{
+				{ChatId: "5521999990001", Variables: QpVariables{"name": "Ana"}},
+				{ChatId: "5521999990002", Variables: QpVariables{"name": "Bruno"}},
+			},
+		},
+		{
+			name:    "semicolon, recipient not first",
+			content: "name;chatid;code\nAna;5521999990001;10\n",
+			recipients: []QpCampaignRecipient{
+				{ChatId: "5521999990001", Variables: QpVariables{"name": "Ana", "code": "10"}},
+			},
+		},
+		{
+			name:    "bom and blank recipients",
+			content: "\ufeffnumber\n5521999990001\n\n ,\n",
+			recipients: []QpCampaignRecipient{
+				{ChatId: "5521999990001"},
+			},
+		},
+		{
+			name:    "without known header uses first column",
+			content: "destination,city\n5521999990001,Rio\n",
+			recipients: []QpCampaignRecipient{
+				{ChatId: "5521999990001", Variables: QpVariables{"city": "Rio"}},
+			},
+		},
+		{name: "header only", content: "phone,name\n", fails: true},
+		{name: "empty", content: "", fails: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			recipients, err := ParseCampaignRecipientsCSV(strings.NewReader(c.content))
+			if c.fails {
+				if err == nil {
+					t.Fatalf("expected error, got: %v", recipients)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if len(recipients) != len(c.recipients) {
+				t.Fatalf("expected %v recipients, got: %v", len(c.recipients), len(recipients))
+			}
+
+			for index, expected := range c.recipients {
+				if recipients[index].ChatId != expected.ChatId || !reflect.DeepEqual(recipients[index].Variables, expected.Variables) {
+					t.Errorf("recipient %v, expected: %v, got: %v", index, expected, *recipients[index])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package models
+
+type QpCampaignResponse struct {
+	QpResponse
+	Total      uint                   `json:"total,omitempty"`
+	Campaign   *QpCampaign            `json:"campaign,omitempty"`   // single campaign
+	Campaigns  []*QpCampaign          `json:"campaigns,omitempty"`  // filtered campaigns
+	Recipients []*QpCampaignRecipient `json:"recipients,omitempty"` // recipients of single campaign
+}
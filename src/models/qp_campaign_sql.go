@@ -0,0 +1,132 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type QpCampaignSql struct {
+	db *sqlx.DB
+}
+
+func (source QpCampaignSql) Find(context string, id string) (*QpCampaign, error) {
+	var result QpCampaign
+	err := source.db.Get(&result, "SELECT * FROM campaigns WHERE context = ? AND id = ?", context, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &result, err
+}
+
+func (source QpCampaignSql) FindAll(context string, status QpCampaignStatus) ([]*QpCampaign, error) {
+	result := []*QpCampaign{}
+	if len(status) == 0 {
+		err := source.db.Select(&result, "SELECT * FROM campaigns WHERE context = ? ORDER BY created", context)
+		return result, err
+	}
+
+	err := source.db.Select(&result, "SELECT * FROM campaigns WHERE context = ? AND status = ? ORDER BY created", context, status)
+	return result, err
+}
+
+func (source QpCampaignSql) Add(element QpCampaign) error {
+	query := `INSERT INTO campaigns (id, context, name, status, pacing, payload, created, updated) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := source.db.Exec(query, element.ID, element.Context, element.Name, element.Status, element.Pacing, element.Payload, element.Created, element.Updated)
+	return err
+}
+
+func (source QpCampaignSql) Update(element QpCampaign) error {
+	query := `UPDATE campaigns SET status = ?, updated = ? WHERE context = ? AND id = ?`
+	_, err := source.db.Exec(query, element.Status, element.Updated, element.Context, element.ID)
+	return err
+}
+
+func (source QpCampaignSql) AddRecipients(campaign string, recipients []*QpCampaignRecipient) (err error) {
+	tx, err := source.db.Beginx()
+	if err != nil {
+		return
+	}
+
+	query := `INSERT INTO campaign_recipients (campaign, position, chatid, variables, status, messageid, error, updated) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	for _, element := range recipients {
+		_, err = tx.Exec(query, campaign, element.Position, element.ChatId, element.Variables, element.Status, element.MessageId, element.Error, element.Updated)
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (source QpCampaignSql) FindRecipients(campaign string, status QpCampaignRecipientStatus) ([]*QpCampaignRecipient, error) {
+	result := []*QpCampaignRecipient{}
+	if len(status) == 0 {
+		err := source.db.Select(&result, "SELECT * FROM campaign_recipients WHERE campaign = ? ORDER BY position", campaign)
+		return result, err
+	}
+
+	err := source.db.Select(&result, "SELECT * FROM campaign_recipients WHERE campaign = ? AND status = ? ORDER BY position", campaign, status)
+	return result, err
+}
+
+func (source QpCampaignSql) NextRecipient(campaign string) (*QpCampaignRecipient, error) {
+	var result QpCampaignRecipient
+	err := source.db.Get(&result, "SELECT * FROM campaign_recipients WHERE campaign = ? AND status = ? ORDER BY position LIMIT 1", campaign, RecipientQueued)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &result, err
+}
+
+func (source QpCampaignSql) UpdateRecipient(element QpCampaignRecipient) error {
+	query := `UPDATE campaign_recipients SET status = ?, messageid = ?, error = ?, updated = ? WHERE campaign = ? AND chatid = ?`
+	_, err := source.db.Exec(query, element.Status, element.MessageId, element.Error, element.Updated, element.Campaign, element.ChatId)
+	return err
+}
+
+func (source QpCampaignSql) UpdateRecipientByMessage(messageId string, status QpCampaignRecipientStatus) error {
+	var previous []QpCampaignRecipientStatus
+	switch status {
+	case RecipientDelivered:
+		previous = []QpCampaignRecipientStatus{RecipientSent}
+	case RecipientRead:
+		previous = []QpCampaignRecipientStatus{RecipientSent, RecipientDelivered}
+	default:
+		return nil
+	}
+
+	query, args, err := sqlx.In(`UPDATE campaign_recipients SET status = ?, updated = ? WHERE messageid = ? AND status IN (?)`, status, time.Now().UTC(), messageId, previous)
+	if err != nil {
+		return err
+	}
+
+	_, err = source.db.Exec(query, args...)
+	return err
+}
+
+func (source QpCampaignSql) Requeue(campaign string, status QpCampaignRecipientStatus) error {
+	query := `UPDATE campaign_recipients SET status = ?, error = '', updated = ? WHERE campaign = ? AND status = ?`
+	_, err := source.db.Exec(query, RecipientQueued, time.Now().UTC(), campaign, status)
+	return err
+}
+
+func (source QpCampaignSql) Summary(campaign string) (map[QpCampaignRecipientStatus]uint, error) {
+	rows := []struct {
+		Status QpCampaignRecipientStatus `db:"status"`
+		Total  uint                      `db:"total"`
+	}{}
+
+	err := source.db.Select(&rows, "SELECT status, COUNT(*) AS total FROM campaign_recipients WHERE campaign = ? GROUP BY status", campaign)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[QpCampaignRecipientStatus]uint)
+	for _, row := range rows {
+		result[row.Status] = row.Total
+	}
+	return result, nil
+}
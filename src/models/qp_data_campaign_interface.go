@@ -0,0 +1,25 @@
+package models
+
+type QpDataCampaignInterface interface {
+	Find(context string, id string) (*QpCampaign, error)
+	FindAll(context string, status QpCampaignStatus) ([]*QpCampaign, error)
+	Add(element QpCampaign) error
+	Update(element QpCampaign) error
+
+	// Inserts all recipients of a campaign at once
+	AddRecipients(campaign string, recipients []*QpCampaignRecipient) error
+	FindRecipients(campaign string, status QpCampaignRecipientStatus) ([]*QpCampaignRecipient, error)
+
+	// First queued recipient, in order of position
+	NextRecipient(campaign string) (*QpCampaignRecipient, error)
+	UpdateRecipient(element QpCampaignRecipient) error
+
+	// Updates the recipient of a sent message, only forward in order of progress
+	UpdateRecipientByMessage(messageId string, status QpCampaignRecipientStatus) error
+
+	// Turns recipients of a status into queued again
+	Requeue(campaign string, status QpCampaignRecipientStatus) error
+
+	// Recipients count by status
+	Summary(campaign string) (map[QpCampaignRecipientStatus]uint, error)
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type QpDataMessageInterface interface {
+	Find(context string, id string) (*QpMessageRecord, error)
+
+	// Messages after a time, newest first, up to limit
+	FindAll(context string, since time.Time, limit uint) ([]*QpMessageRecord, error)
+
+	// Messages matching a receive filter, newest first, up to limit
+	FindFiltered(context string, filter *QpReceiveFilter, limit uint) ([]*QpMessageRecord, error)
+
+	// Inserts or replaces a message
+	Save(element QpMessageRecord) error
+
+	// Updates status and payload only if the stored status is older, false otherwise or if not found
+	UpdateStatus(element QpMessageRecord) (bool, error)
+
+	// Removes messages older than a time
+	Purge(context string, before time.Time) error
+}
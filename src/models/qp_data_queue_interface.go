@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type QpDataQueueInterface interface {
+	Find(context string, id string) (*QpQueueItem, error)
+	FindAll(context string, status QpQueueStatus) ([]*QpQueueItem, error)
+
+	// Pending items ordered by creation, oldest first
+	FindPending(context string, limit uint) ([]*QpQueueItem, error)
+	Add(element QpQueueItem) error
+	Update(element QpQueueItem) error
+
+	// Cancels only if still pending or scheduled, false if not found or already claimed
+	Cancel(context string, id string, updated time.Time) (bool, error)
+
+	// Marks a pending item as sending, false if it was canceled or claimed before
+	Claim(context string, id string, updated time.Time) (bool, error)
+
+	// Returns items interrupted while sending to pending, after a restart
+	Release(context string) error
+
+	// Turns scheduled items into pending when its time arrives
+	Promote(context string, until time.Time) error
+
+	// Removes finished items older than a time
+	Purge(context string, before time.Time) error
+}
@@ -0,0 +1,12 @@
+package models
+
+type QpDataTemplateInterface interface {
+	Find(userId string, id string) (*QpTemplate, error)
+	FindByName(userId string, context string, name string) (*QpTemplate, error)
+
+	// Templates of a user, only available to this bot if context is not empty
+	FindAll(userId string, context string) ([]*QpTemplate, error)
+	Add(element QpTemplate) error
+	Update(element QpTemplate) error
+	Delete(userId string, id string) error
+}
@@ -26,6 +26,10 @@ type QPDatabase struct {
 	User       IQPUser
 	Bot        IQPBot
 	Webhook    QpDataWebhookInterface
+	Queue      QpDataQueueInterface
+	Campaign   QpDataCampaignInterface
+	Template   QpDataTemplateInterface
+	Message    QpDataMessageInterface
 }
 
 var (
@@ -66,6 +70,10 @@ func GetDatabase() *QPDatabase {
 	var iuser IQPUser
 	var ibot IQPBot
 	var iwebhook = QpBotWebhookSql{db}
+	var iqueue = QpQueueSql{db}
+	var icampaign = QpCampaignSql{db}
+	var itemplate = QpTemplateSql{db}
+	var imessage = QpMessageSql{db}
 
 	if config.Driver == "postgres" {
 		istore = QPStorePostgres{db}
@@ -79,7 +87,7 @@ func GetDatabase() *QPDatabase {
 		log.Fatal("database driver not supported")
 	}
 
-	return &QPDatabase{config, db, istore, iuser, ibot, iwebhook}
+	return &QPDatabase{config, db, istore, iuser, ibot, iwebhook, iqueue, icampaign, itemplate, imessage}
 }
 
 func GetDBConfig() QPDatabaseConfig {
@@ -0,0 +1,7 @@
+package models
+
+// Request to replace the text of a previous sent message
+type QpEditRequest struct {
+	// New content of the message
+	Text string `json:"text"`
+}
@@ -0,0 +1,14 @@
+package models
+
+// Parameters to be acessed/passed on Views (campaigns.tmpl, campaign.tmpl)
+type QPFormCampaignData struct {
+	PageTitle           string
+	ErrorMessage        string
+	ServerID            string
+	Number              string
+	FormAccountEndpoint string
+	Campaigns           []*QpCampaign
+	Campaign            *QpCampaign
+	Recipients          []*QpCampaignRecipient
+	Statuses            []QpCampaignRecipientStatus
+}
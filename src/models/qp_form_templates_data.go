@@ -0,0 +1,11 @@
+package models
+
+// Parameters to be acessed/passed on Views (templates.tmpl)
+type QPFormTemplatesData struct {
+	PageTitle    string
+	ErrorMessage string
+	User         QPUser
+	Servers      map[string]*QPWhatsappServer
+	Templates    []*QpTemplate
+	Template     *QpTemplate // editing or creating
+}
@@ -0,0 +1,22 @@
+package models
+
+import "fmt"
+
+/*
+<summary>
+	Request to forward a cached message to another chat
+</summary>
+*/
+type QpForwardRequest struct {
+	QpSendRequest
+
+	// Message to forward, must be on cache
+	MessageId string `json:"messageId"`
+}
+
+func (source *QpForwardRequest) Validate() (err error) {
+	if len(source.MessageId) == 0 {
+		err = fmt.Errorf("message id missing")
+	}
+	return
+}
@@ -0,0 +1,204 @@
+package models
+
+import (
+	"container/list"
+	"strings"
+	"time"
+
+	metrics "github.com/sufficit/sufficit-quepasa/metrics"
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+// Fixed memory estimated for each message, besides text and attachments
+const CacheEntryOverhead = 512
+
+// Interval between each look for expired messages
+const CacheSweepInterval = time.Minute
+
+type qpMessageCacheEntry struct {
+	id      string
+	msg     whatsapp.WhatsappMessage
+	size    uint64
+	created time.Time // used only for messages without timestamp
+}
+
+/*
+<summary>
+	In memory messages of a single server, least recently used are evicted first
+	Messages older than max age, by its own timestamp, are evicted too, not safe for concurrent use
+</summary>
+*/
+type QpMessageCache struct {
+	Policy QpCachePolicy
+
+	context string
+	entries map[string]*list.Element
+	order   *list.List // front is the most recently used
+	bytes   uint64
+	swept   time.Time
+}
+
+func NewQpMessageCache(context string, policy QpCachePolicy) *QpMessageCache {
+	return &QpMessageCache{
+		Policy:  policy,
+		context: context,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		swept:   time.Now(),
+	}
+}
+
+// Adds or replaces a message, evicting others if limits are exceeded
+func (source *QpMessageCache) Set(msg whatsapp.WhatsappMessage) {
+	id := strings.ToUpper(msg.Id)
+	entry := &qpMessageCacheEntry{
+		id:      id,
+		msg:     msg,
+		size:    GetMessageCacheSize(&msg),
+		created: time.Now(),
+	}
+
+	if element, ok := source.entries[id]; ok {
+		previous := element.Value.(*qpMessageCacheEntry)
+		entry.created = previous.created
+
+		// already too old, as history messages, keeping only on store
+		if source.expired(entry) {
+			source.remove(element)
+			source.report()
+			return
+		}
+
+		source.bytes -= previous.size
+		element.Value = entry
+		source.order.MoveToFront(element)
+	} else if source.expired(entry) {
+		return
+	} else {
+		source.entries[id] = source.order.PushFront(entry)
+	}
+
+	source.bytes += entry.size
+	source.evict()
+}
+
+// Gets a message, marking it as recently used
+func (source *QpMessageCache) Get(id string) (msg whatsapp.WhatsappMessage, ok bool) {
+	element, ok := source.entries[strings.ToUpper(id)]
+	if !ok {
+		return
+	}
+
+	entry := element.Value.(*qpMessageCacheEntry)
+	if source.expired(entry) {
+		source.remove(element)
+		source.report()
+		return msg, false
+	}
+
+	source.order.MoveToFront(element)
+	return entry.msg, true
+}
+
+// Messages with timestamp after a time, without changing the usage order
+func (source *QpMessageCache) GetAll(timestamp time.Time) (messages []whatsapp.WhatsappMessage) {
+	for element := source.order.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*qpMessageCacheEntry)
+		if !source.expired(entry) && entry.msg.Timestamp.After(timestamp) {
+			messages = append(messages, entry.msg)
+		}
+	}
+	return
+}
+
+func (source *QpMessageCache) Len() int {
+	return source.order.Len()
+}
+
+// Estimated memory in use
+func (source *QpMessageCache) Bytes() uint64 {
+	return source.bytes
+}
+
+// Replaces the limits, evicting messages that exceeds the new ones
+func (source *QpMessageCache) SetPolicy(policy QpCachePolicy) {
+	source.Policy = policy
+	source.swept = time.Time{}
+	source.evict()
+}
+
+// Removes all messages and its metrics
+func (source *QpMessageCache) Clear() {
+	source.entries = make(map[string]*list.Element)
+	source.order.Init()
+	source.bytes = 0
+	metrics.CacheMessages.DeleteLabelValues(source.context)
+	metrics.CacheBytes.DeleteLabelValues(source.context)
+}
+
+// Aged by the message timestamp, arrival time only if missing
+func (source *QpMessageCache) expired(entry *qpMessageCacheEntry) bool {
+	if source.Policy.MaxAge <= 0 {
+		return false
+	}
+
+	timestamp := entry.msg.Timestamp
+	if timestamp.IsZero() {
+		timestamp = entry.created
+	}
+	return time.Since(timestamp) > source.Policy.MaxAge
+}
+
+func (source *QpMessageCache) exceeded() bool {
+	if source.Policy.MaxEntries > 0 && uint(source.order.Len()) > source.Policy.MaxEntries {
+		return true
+	}
+	return source.Policy.MaxBytes > 0 && source.bytes > source.Policy.MaxBytes
+}
+
+func (source *QpMessageCache) evict() {
+
+	// looking for expired messages, once per interval
+	if source.Policy.MaxAge > 0 && time.Since(source.swept) > CacheSweepInterval {
+		source.swept = time.Now()
+		for element := source.order.Back(); element != nil; {
+			previous := element.Prev()
+			if source.expired(element.Value.(*qpMessageCacheEntry)) {
+				source.remove(element)
+			}
+			element = previous
+		}
+	}
+
+	// least recently used first, always keeping the newest one
+	for source.exceeded() && source.order.Len() > 1 {
+		source.remove(source.order.Back())
+	}
+
+	source.report()
+}
+
+func (source *QpMessageCache) remove(element *list.Element) {
+	entry := source.order.Remove(element).(*qpMessageCacheEntry)
+	delete(source.entries, entry.id)
+	source.bytes -= entry.size
+	metrics.CacheEvictions.Inc()
+}
+
+func (source *QpMessageCache) report() {
+	metrics.CacheMessages.WithLabelValues(source.context).Set(float64(source.order.Len()))
+	metrics.CacheBytes.WithLabelValues(source.context).Set(float64(source.bytes))
+}
+
+// Estimated memory of a message, text, attachment content and original message
+func GetMessageCacheSize(msg *whatsapp.WhatsappMessage) (size uint64) {
+	size = CacheEntryOverhead + uint64(len(msg.Text))
+	if msg.Attachment != nil {
+		if content := msg.Attachment.GetContent(); content != nil {
+			size += uint64(len(*content))
+		}
+	}
+
+	size += uint64(GetWhatsmeowContentSize(msg.Content))
+	return
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+func newTestCache(t *testing.T, policy QpCachePolicy) *QpMessageCache {
+	cache := NewQpMessageCache("test-"+t.Name(), policy)
+	t.Cleanup(cache.Clear)
+	return cache
+}
+
+func assertCached(t *testing.T, cache *QpMessageCache, id string, expected bool) {
+	t.Helper()
+	if _, ok := cache.Get(id); ok != expected {
+		t.Errorf("message %s, expected cached: %v", id, expected)
+	}
+}
+
+func TestMessageCacheLeastRecentlyUsed(t *testing.T) {
+	cache := newTestCache(t, QpCachePolicy{MaxEntries: 2})
+
+	cache.Set(whatsapp.WhatsappMessage{Id: "a", Timestamp: time.Now()})
+	cache.Set(whatsapp.WhatsappMessage{Id: "b", Timestamp: time.Now()})
+	cache.Get("A") // ids are case insensitive, "a" becomes the most recent
+	cache.Set(whatsapp.WhatsappMessage{Id: "c", Timestamp: time.Now()})
+
+	assertCached(t, cache, "a", true)
+	assertCached(t, cache, "b", false)
+	assertCached(t, cache, "c", true)
+}
+
+func TestMessageCacheMaxBytes(t *testing.T) {
+	cache := newTestCache(t, QpCachePolicy{MaxBytes: CacheEntryOverhead*2 + 20})
+	for _, id := range []string{"a", "b", "c"} {
+		cache.Set(whatsapp.WhatsappMessage{Id: id, Text: "0123456789", Timestamp: time.Now()})
+	}
+
+	assertCached(t, cache, "a", false)
+	if cache.Len() != 2 || cache.Bytes() > cache.Policy.MaxBytes {
+		t.Errorf("expected 2 messages within limit, got %v messages, %v bytes", cache.Len(), cache.Bytes())
+	}
+}
+
+func TestMessageCacheMaxAge(t *testing.T) {
+	cache := newTestCache(t, QpCachePolicy{MaxAge: time.Hour})
+
+	// history messages are aged by its timestamp, not by arrival
+	cache.Set(whatsapp.WhatsappMessage{Id: "history", Timestamp: time.Now().Add(-2 * time.Hour)})
+	cache.Set(whatsapp.WhatsappMessage{Id: "live", Timestamp: time.Now()})
+	cache.Set(whatsapp.WhatsappMessage{Id: "untimed"})
+
+	assertCached(t, cache, "history", false)
+	assertCached(t, cache, "live", true)
+	assertCached(t, cache, "untimed", true)
+
+	if messages := cache.GetAll(time.Now().Add(-3 * time.Hour)); len(messages) != 1 || messages[0].Id != "live" {
+		t.Errorf("expected only the live message, got %v", messages)
+	}
+}
+
+func TestMessageCacheUnlimited(t *testing.T) {
+	cache := newTestCache(t, QpCachePolicy{})
+	cache.Set(whatsapp.WhatsappMessage{Id: "old", Timestamp: time.Now().Add(-48 * time.Hour)})
+	cache.Set(whatsapp.WhatsappMessage{Id: "new", Timestamp: time.Now()})
+
+	if cache.Len() != 2 {
+		t.Errorf("expected nothing evicted, got %v messages", cache.Len())
+	}
+}
+
+func TestMessageCacheSetPolicy(t *testing.T) {
+	cache := newTestCache(t, QpCachePolicy{})
+	for _, id := range []string{"a", "b", "c"} {
+		cache.Set(whatsapp.WhatsappMessage{Id: id, Timestamp: time.Now()})
+	}
+
+	cache.SetPolicy(QpCachePolicy{MaxEntries: 1})
+	if cache.Len() != 1 {
+		t.Fatalf("expected 1 message after reducing the policy, got %v", cache.Len())
+	}
+	assertCached(t, cache, "c", true)
+}
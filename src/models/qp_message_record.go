@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+// Received or sent message, stored on database
+type QpMessageRecord struct {
+	Context   string    `db:"context"` // bot that received or sent this message
+	ID        string    `db:"id"`      // uppercase message id
+	ChatId    string    `db:"chatid"`
+	TrackId   string    `db:"trackid"`
+	FromMe    bool      `db:"fromme"`
+	Status    string    `db:"status"`
+	Timestamp time.Time `db:"timestamp"`
+	Payload   []byte    `db:"payload"` // serialized message, without original content
+	Content   []byte    `db:"content"` // original message, used to download attachments
+	Secret    []byte    `db:"secret"`  // poll encryption secret, used to decrypt votes
+
+	// copied from payload, used on receive filters
+	FromInternal bool   `db:"frominternal"`
+	Type         string `db:"type"`
+	Text         string `db:"text"`
+}
+
+func NewQpMessageRecord(context string, msg *whatsapp.WhatsappMessage) (record *QpMessageRecord, err error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	content, err := MarshalWhatsmeowContent(msg.Content)
+	if err != nil {
+		return
+	}
+
+	record = &QpMessageRecord{
+		Context:   context,
+		ID:        strings.ToUpper(msg.Id),
+		ChatId:    msg.Chat.ID,
+		TrackId:   msg.TrackId,
+		FromMe:    msg.FromMe,
+		Status:    string(msg.Status),
+		Timestamp: msg.Timestamp.UTC(),
+		Payload:   payload,
+		Content:   content,
+
+		FromInternal: msg.FromInternal,
+		Type:         msg.Type.String(),
+		Text:         msg.Text,
+	}
+
+	// not serialized on payload, avoiding to expose on webhooks
+	if msg.Poll != nil {
+		record.Secret = msg.Poll.Secret
+	}
+	return
+}
+
+func (source *QpMessageRecord) GetMessage() (msg *whatsapp.WhatsappMessage, err error) {
+	msg = &whatsapp.WhatsappMessage{}
+	err = json.Unmarshal(source.Payload, msg)
+	if err != nil {
+		return
+	}
+
+	if msg.Poll != nil && len(source.Secret) > 0 {
+		msg.Poll.Secret = source.Secret
+	}
+
+	msg.Content, err = UnmarshalWhatsmeowContent(source.Content)
+	return
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+func TestMessageRecordRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		msg  whatsapp.WhatsappMessage
+	}{
+		{
+			name: "text",
+			msg:  whatsapp.WhatsappMessage{Id: "3eb0text", Text: "hello", Type: whatsapp.TextMessageType},
+		},
+		{
+			name: "poll with secret",
+			msg: whatsapp.WhatsappMessage{
+				Id:   "3eb0poll",
+				Type: whatsapp.PollMessageType,
+				Poll: &whatsapp.WhatsappPoll{Question: "lunch?", Options: []string{"yes", "no"}, Secret: []byte{9, 8, 7}},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.msg.Chat = whatsapp.WhatsappChat{ID: "5521999999999@s.whatsapp.net"}
+			c.msg.Timestamp = time.Unix(1666000000, 0)
+
+			record, err := NewQpMessageRecord("bot", &c.msg)
+			if err != nil {
+				t.Fatalf("record error: %s", err)
+			}
+
+			if record.ID != strings.ToUpper(c.msg.Id) {
+				t.Errorf("id not normalized: %s", record.ID)
+			}
+
+			restored, err := record.GetMessage()
+			if err != nil {
+				t.Fatalf("restore error: %s", err)
+			}
+
+			if restored.Text != c.msg.Text || restored.Chat.ID != c.msg.Chat.ID {
+				t.Errorf("restored message differs: %v", restored)
+			}
+
+			if c.msg.Poll != nil {
+				if restored.Poll == nil || string(restored.Poll.Secret) != string(c.msg.Poll.Secret) {
+					t.Errorf("poll secret not restored: %v", restored.Poll)
+				}
+			}
+		})
+	}
+}
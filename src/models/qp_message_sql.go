@@ -0,0 +1,149 @@
+package models
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+type QpMessageSql struct {
+	db *sqlx.DB
+}
+
+func (source QpMessageSql) Find(context string, id string) (*QpMessageRecord, error) {
+	var result QpMessageRecord
+	err := source.db.Get(&result, "SELECT * FROM messages WHERE context = ? AND id = ?", context, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &result, err
+}
+
+func (source QpMessageSql) FindAll(context string, since time.Time, limit uint) ([]*QpMessageRecord, error) {
+	result := []*QpMessageRecord{}
+	err := source.db.Select(&result, "SELECT * FROM messages WHERE context = ? AND timestamp > ? ORDER BY timestamp DESC LIMIT ?", context, since, limit)
+	return result, err
+}
+
+// Builds the conditions of a receive filter, the same ones used for in memory messages
+func (source QpMessageSql) FindFiltered(context string, filter *QpReceiveFilter, limit uint) ([]*QpMessageRecord, error) {
+	conditions := []string{"context = ?"}
+	args := []interface{}{context}
+
+	if !filter.After.IsZero() {
+		conditions = append(conditions, "timestamp > ?")
+		args = append(args, filter.After.UTC())
+	}
+
+	if !filter.Before.IsZero() {
+		conditions = append(conditions, "timestamp < ?")
+		args = append(args, filter.Before.UTC())
+	}
+
+	if len(filter.ChatId) > 0 {
+		conditions = append(conditions, "chatid = ?")
+		args = append(args, filter.ChatId)
+	}
+
+	if filter.Type != nil {
+		conditions = append(conditions, "type = ?")
+		args = append(args, filter.Type.String())
+	}
+
+	if filter.FromMe != nil {
+		conditions = append(conditions, "fromme = ?")
+		args = append(args, *filter.FromMe)
+	}
+
+	if filter.FromInternal != nil {
+		conditions = append(conditions, "frominternal = ?")
+		args = append(args, *filter.FromInternal)
+	}
+
+	if len(filter.TrackId) > 0 {
+		conditions = append(conditions, "trackid = ?")
+		args = append(args, filter.TrackId)
+	}
+
+	if len(filter.Search) > 0 {
+		conditions = append(conditions, "LOWER(text) LIKE ?")
+		args = append(args, "%"+strings.ToLower(filter.Search)+"%")
+	}
+
+	// position after the last message of the previous page, newest first, then by id
+	if len(filter.Cursor) > 0 {
+		timestamp, id, err := DecodeReceiveCursor(filter.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		conditions = append(conditions, "(timestamp < ? OR (timestamp = ? AND id > ?))")
+		args = append(args, timestamp.UTC(), timestamp.UTC(), strings.ToUpper(id))
+	}
+
+	args = append(args, limit)
+	query := "SELECT * FROM messages WHERE " + strings.Join(conditions, " AND ") + " ORDER BY timestamp DESC, id LIMIT ?"
+
+	result := []*QpMessageRecord{}
+	err := source.db.Select(&result, query, args...)
+	return result, err
+}
+
+func (source QpMessageSql) Save(element QpMessageRecord) error {
+	tx, err := source.db.Beginx()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`DELETE FROM messages WHERE context = ? AND id = ?`, element.Context, element.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	query := `INSERT INTO messages (context, id, chatid, trackid, fromme, frominternal, type, text, status, timestamp, payload, content, secret) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = tx.Exec(query, element.Context, element.ID, element.ChatId, element.TrackId, element.FromMe, element.FromInternal, element.Type, element.Text, element.Status, element.Timestamp, element.Payload, element.Content, element.Secret)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Conditional on the stored status, concurrent receipts never move it backwards
+func (source QpMessageSql) UpdateStatus(element QpMessageRecord) (bool, error) {
+	status := whatsapp.WhatsappMessageStatus(element.Status)
+	conditions := []string{}
+	args := []interface{}{element.Status, element.Payload, element.Context, element.ID}
+	for _, known := range whatsapp.MessageStatuses {
+		if !status.IsAfter(known) {
+			conditions = append(conditions, "?")
+			args = append(args, known)
+		}
+	}
+
+	query := `UPDATE messages SET status = ?, payload = ? WHERE context = ? AND id = ?`
+	if len(conditions) > 0 {
+		query += " AND status NOT IN (" + strings.Join(conditions, ", ") + ")"
+	}
+
+	result, err := source.db.Exec(query, args...)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (source QpMessageSql) Purge(context string, before time.Time) error {
+	query := `DELETE FROM messages WHERE context = ? AND timestamp < ?`
+	_, err := source.db.Exec(query, context, before)
+	return err
+}
@@ -0,0 +1,199 @@
+package models
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	log "github.com/sirupsen/logrus"
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+func newTestMessageSql(t *testing.T) QpMessageSql {
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	schema := []string{
+		"CREATE TABLE messages (`context` VARCHAR (255) NOT NULL, `id` VARCHAR (255) NOT NULL, `chatid` VARCHAR (255) NOT NULL, `trackid` VARCHAR (100) NOT NULL DEFAULT '', `fromme` BOOLEAN NOT NULL DEFAULT FALSE, `status` VARCHAR (20) NOT NULL DEFAULT '', `timestamp` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, `payload` BLOB NOT NULL, `content` BLOB DEFAULT NULL, CONSTRAINT messages_pkey PRIMARY KEY (`context`, `id`))",
+		"ALTER TABLE messages ADD COLUMN secret BLOB DEFAULT NULL",
+		"ALTER TABLE messages ADD COLUMN frominternal BOOLEAN NOT NULL DEFAULT FALSE",
+		"ALTER TABLE messages ADD COLUMN type VARCHAR (20) NOT NULL DEFAULT ''",
+		"ALTER TABLE messages ADD COLUMN text TEXT NOT NULL DEFAULT ''",
+	}
+
+	for _, statement := range schema {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("schema error: %s", err)
+		}
+	}
+	return QpMessageSql{db: db}
+}
+
+func TestMessageSqlFindFiltered(t *testing.T) {
+	source := newTestMessageSql(t)
+	base := time.Unix(1666000000, 0)
+	fromMe := true
+	image := whatsapp.ImageMessageType
+
+	messages := []whatsapp.WhatsappMessage{
+		{Id: "A1", Chat: whatsapp.WhatsappChat{ID: "a@s.whatsapp.net"}, Text: "Hello World", Type: whatsapp.TextMessageType, Timestamp: base.Add(1 * time.Second)},
+		{Id: "A2", Chat: whatsapp.WhatsappChat{ID: "a@s.whatsapp.net"}, Type: whatsapp.ImageMessageType, FromMe: true, Timestamp: base.Add(2 * time.Second)},
+		{Id: "B1", Chat: whatsapp.WhatsappChat{ID: "b@s.whatsapp.net"}, Text: "other", Type: whatsapp.TextMessageType, TrackId: "crm", Timestamp: base.Add(3 * time.Second)},
+		{Id: "B2", Chat: whatsapp.WhatsappChat{ID: "b@s.whatsapp.net"}, Text: "world news", Type: whatsapp.TextMessageType, Timestamp: base.Add(4 * time.Second)},
+	}
+
+	for index := range messages {
+		record, err := NewQpMessageRecord("bot", &messages[index])
+		if err != nil {
+			t.Fatalf("record error: %s", err)
+		}
+
+		if err = source.Save(*record); err != nil {
+			t.Fatalf("save error: %s", err)
+		}
+	}
+
+	cases := []struct {
+		name   string
+		filter QpReceiveFilter
+		limit  uint
+		ids    []string
+	}{
+		{name: "all newest first", filter: QpReceiveFilter{}, limit: 10, ids: []string{"B2", "B1", "A2", "A1"}},
+		{name: "limit", filter: QpReceiveFilter{}, limit: 2, ids: []string{"B2", "B1"}},
+		{name: "chat", filter: QpReceiveFilter{ChatId: "a@s.whatsapp.net"}, limit: 10, ids: []string{"A2", "A1"}},
+		{name: "type", filter: QpReceiveFilter{Type: &image}, limit: 10, ids: []string{"A2"}},
+		{name: "from me", filter: QpReceiveFilter{FromMe: &fromMe}, limit: 10, ids: []string{"A2"}},
+		{name: "track id", filter: QpReceiveFilter{TrackId: "crm"}, limit: 10, ids: []string{"B1"}},
+		{name: "search", filter: QpReceiveFilter{Search: "WORLD"}, limit: 10, ids: []string{"B2", "A1"}},
+		{name: "after", filter: QpReceiveFilter{After: base.Add(2 * time.Second)}, limit: 10, ids: []string{"B2", "B1"}},
+		{name: "before", filter: QpReceiveFilter{Before: base.Add(2 * time.Second)}, limit: 10, ids: []string{"A1"}},
+		{name: "cursor", filter: QpReceiveFilter{Cursor: EncodeReceiveCursor(&messages[2])}, limit: 10, ids: []string{"A2", "A1"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			records, err := source.FindFiltered("bot", &c.filter, c.limit)
+			if err != nil {
+				t.Fatalf("find error: %s", err)
+			}
+
+			var ids []string
+			for _, record := range records {
+				ids = append(ids, record.ID)
+			}
+
+			if len(ids) != len(c.ids) {
+				t.Fatalf("expected: %v, got: %v", c.ids, ids)
+			}
+
+			for index := range ids {
+				if ids[index] != c.ids[index] {
+					t.Fatalf("expected: %v, got: %v", c.ids, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestMessageSqlUpdateStatusForwardOnly(t *testing.T) {
+	source := newTestMessageSql(t)
+	msg := &whatsapp.WhatsappMessage{Id: "S1", Chat: whatsapp.WhatsappChat{ID: "a@s.whatsapp.net"}, Status: whatsapp.MessageStatusSent}
+
+	updateStatus := func(status whatsapp.WhatsappMessageStatus) bool {
+		msg.Status = status
+		record, err := NewQpMessageRecord("bot", msg)
+		if err != nil {
+			t.Fatalf("record error: %s", err)
+		}
+
+		updated, err := source.UpdateStatus(*record)
+		if err != nil {
+			t.Fatalf("update error: %s", err)
+		}
+		return updated
+	}
+
+	if updateStatus(whatsapp.MessageStatusRead) {
+		t.Fatal("not stored message should not be updated")
+	}
+
+	record, _ := NewQpMessageRecord("bot", &whatsapp.WhatsappMessage{Id: "S1", Chat: msg.Chat, Status: whatsapp.MessageStatusSent})
+	if err := source.Save(*record); err != nil {
+		t.Fatalf("save error: %s", err)
+	}
+
+	if !updateStatus(whatsapp.MessageStatusRead) {
+		t.Error("read should update a sent message")
+	}
+
+	// late delivery receipt, after the read one
+	if updateStatus(whatsapp.MessageStatusDelivered) || updateStatus(whatsapp.MessageStatusRead) {
+		t.Error("status should only move forward")
+	}
+
+	stored, err := source.Find("bot", "S1")
+	if err != nil || stored == nil || stored.Status != string(whatsapp.MessageStatusRead) {
+		t.Fatalf("expected read, got: %v, %v", stored, err)
+	}
+
+	restored, _ := stored.GetMessage()
+	if restored.Status != whatsapp.MessageStatusRead {
+		t.Errorf("payload status differs: %s", restored.Status)
+	}
+}
+
+func TestMessageStoreFindAboveQueryLimit(t *testing.T) {
+	source := newTestMessageSql(t)
+	store := NewQpMessageStore("bot", source, MessagesDefaultRetention, log.NewEntry(log.StandardLogger()))
+	base := time.Unix(1666000000, 0)
+
+	total := int(MessagesDefaultQueryLimit) + 5
+	for index := 0; index < total; index++ {
+		msg := &whatsapp.WhatsappMessage{Id: fmt.Sprintf("M%04d", index), Chat: whatsapp.WhatsappChat{ID: "a@s.whatsapp.net"}, Timestamp: base.Add(time.Duration(index) * time.Second)}
+		record, err := NewQpMessageRecord("bot", msg)
+		if err != nil {
+			t.Fatalf("record error: %s", err)
+		}
+
+		if err = source.Save(*record); err != nil {
+			t.Fatalf("save error: %s", err)
+		}
+	}
+
+	// without limit, capped at the max and continued by cursor
+	filter := &QpReceiveFilter{}
+	seen := map[string]bool{}
+	for pages := 0; pages < 3; pages++ {
+		found, err := store.Find(filter)
+		if err != nil {
+			t.Fatalf("find error: %s", err)
+		}
+
+		page, next, err := filter.Apply(found)
+		if err != nil {
+			t.Fatalf("apply error: %s", err)
+		}
+
+		if pages == 0 && (uint(len(page)) != MessagesDefaultQueryLimit || len(next) == 0) {
+			t.Fatalf("first page should be capped with a cursor, got: %v, %q", len(page), next)
+		}
+
+		for _, msg := range page {
+			seen[msg.Id] = true
+		}
+
+		if len(next) == 0 {
+			break
+		}
+		filter.Cursor = next
+	}
+
+	if len(seen) != total {
+		t.Errorf("expected all %v messages through pages, got: %v", total, len(seen))
+	}
+}
@@ -0,0 +1,157 @@
+package models
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+// Default period that messages are kept on database
+const MessagesDefaultRetention = 30 * 24 * time.Hour
+
+// Max messages restored from database on a single query
+const MessagesDefaultQueryLimit uint = 1000
+
+/*
+<summary>
+	Persistent history of a single server, survives restarts
+	Old messages are removed following the retention period, once per hour
+</summary>
+*/
+type QpMessageStore struct {
+	context   string
+	db        QpDataMessageInterface
+	retention time.Duration
+	log       *log.Entry
+
+	sync   *sync.Mutex
+	purged time.Time
+}
+
+func NewQpMessageStore(context string, db QpDataMessageInterface, retention time.Duration, logger *log.Entry) *QpMessageStore {
+	return &QpMessageStore{
+		context:   context,
+		db:        db,
+		retention: retention,
+		log:       logger,
+		sync:      &sync.Mutex{},
+	}
+}
+
+func (source *QpMessageStore) Save(msg *whatsapp.WhatsappMessage) {
+	record, err := NewQpMessageRecord(source.context, msg)
+	if err == nil {
+		err = source.db.Save(*record)
+	}
+
+	if err != nil {
+		source.log.Errorf("error on storing message: %s, %s", msg.Id, err)
+	}
+
+	source.purge()
+}
+
+// Stores a newer status, keeping the stored one if already after, inserting if not stored yet
+func (source *QpMessageStore) SaveStatus(msg *whatsapp.WhatsappMessage) {
+	record, err := NewQpMessageRecord(source.context, msg)
+	if err != nil {
+		source.log.Errorf("error on storing message status: %s, %s", msg.Id, err)
+		return
+	}
+
+	updated, err := source.db.UpdateStatus(*record)
+	if err == nil && !updated {
+		var stored *QpMessageRecord
+		stored, err = source.db.Find(source.context, record.ID)
+		if err == nil && stored == nil {
+			err = source.db.Save(*record)
+		}
+	}
+
+	if err != nil {
+		source.log.Errorf("error on storing message status: %s, %s", msg.Id, err)
+	}
+}
+
+func (source *QpMessageStore) Get(id string) (msg *whatsapp.WhatsappMessage, err error) {
+	record, err := source.db.Find(source.context, strings.ToUpper(id))
+	if err != nil || record == nil {
+		return
+	}
+
+	return record.GetMessage()
+}
+
+// Newest messages after a time, up to limit, ignoring the ones that could not be restored
+func (source *QpMessageStore) GetAll(since time.Time, limit uint) (messages []whatsapp.WhatsappMessage) {
+	records, err := source.db.FindAll(source.context, since.UTC(), limit)
+	if err != nil {
+		source.log.Errorf("error on getting stored messages: %s", err)
+		return
+	}
+
+	for _, record := range records {
+		msg, err := record.GetMessage()
+		if err != nil {
+			source.log.Warnf("error on restoring stored message: %s, %s", record.ID, err)
+			continue
+		}
+		messages = append(messages, *msg)
+	}
+	return
+}
+
+// Messages matching a receive filter, one more than its limit, used to know if there is a next page
+func (source *QpMessageStore) Find(filter *QpReceiveFilter) (messages []whatsapp.WhatsappMessage, err error) {
+	records, err := source.db.FindFiltered(source.context, filter, filter.GetLimit()+1)
+	if err != nil {
+		return
+	}
+
+	for _, record := range records {
+		msg, err := record.GetMessage()
+		if err != nil {
+			source.log.Warnf("error on restoring stored message: %s, %s", record.ID, err)
+			continue
+		}
+		messages = append(messages, *msg)
+	}
+	return
+}
+
+// Keeps the newest status of a stored message, filling the receipt track id
+func (source *QpMessageStore) Receipt(receipt *whatsapp.WhatsappReceipt) {
+	msg, err := source.Get(receipt.Id)
+	if err != nil || msg == nil {
+		return
+	}
+
+	if len(receipt.TrackId) == 0 {
+		receipt.TrackId = msg.TrackId
+	}
+
+	if receipt.Status.IsAfter(msg.Status) {
+		msg.Status = receipt.Status
+		source.SaveStatus(msg)
+	}
+}
+
+// Removes old messages, once per hour
+func (source *QpMessageStore) purge() {
+	source.sync.Lock()
+	if time.Since(source.purged) < time.Hour {
+		source.sync.Unlock()
+		return
+	}
+
+	source.purged = time.Now()
+	source.sync.Unlock()
+
+	err := source.db.Purge(source.context, time.Now().UTC().Add(-source.retention))
+	if err != nil {
+		source.log.Errorf("error on purging stored messages: %s", err)
+	}
+}
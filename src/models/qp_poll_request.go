@@ -0,0 +1,80 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+// Limits accepted by whatsapp official clients
+const (
+	PollOptionsMin = 2
+	PollOptionsMax = 12
+)
+
+/*
+<summary>
+	Request to send a native poll
+	Votes are received as poll vote messages
+</summary>
+*/
+type QpPollRequest struct {
+	QpSendRequest
+
+	// Question of the poll
+	Question string `json:"question"`
+
+	// Names of the options, must be unique
+	Options []string `json:"options"`
+
+	// (Optional) Allows voters to select more than one option
+	Multiple bool `json:"multiple,omitempty"`
+}
+
+// Checks question and options
+func (source *QpPollRequest) Validate() error {
+	if len(strings.TrimSpace(source.Question)) == 0 {
+		return fmt.Errorf("question missing")
+	}
+
+	if len(source.Options) < PollOptionsMin || len(source.Options) > PollOptionsMax {
+		return fmt.Errorf("invalid options count: %v, must be between %v and %v", len(source.Options), PollOptionsMin, PollOptionsMax)
+	}
+
+	unique := make(map[string]bool)
+	for _, option := range source.Options {
+		if len(strings.TrimSpace(option)) == 0 {
+			return fmt.Errorf("empty option not allowed")
+		}
+
+		if unique[option] {
+			return fmt.Errorf("duplicated option: %s", option)
+		}
+		unique[option] = true
+	}
+
+	return nil
+}
+
+func (source *QpPollRequest) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage, err error) {
+	msg, err = source.QpSendRequest.ToWhatsappMessage()
+	if err != nil {
+		return
+	}
+
+	// single choice by default
+	var selectable uint32 = 1
+	if source.Multiple {
+		selectable = 0
+	}
+
+	msg.Type = whatsapp.PollMessageType
+	msg.Text = source.Question
+	msg.Poll = &whatsapp.WhatsappPoll{
+		Question:   source.Question,
+		Options:    source.Options,
+		Selectable: selectable,
+	}
+	return
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+/*
+<summary>
+	Request to show typing indicators on a chat or set the account availability
+	On chats: composing, recording, paused
+	On account: available, unavailable
+</summary>
+*/
+type QpPresenceRequest struct {
+	ChatId   string `json:"chatId,omitempty"`
+	Presence string `json:"presence"`
+}
+
+func (source *QpPresenceRequest) GetChatPresence() (whatsapp.WhatsappChatPresence, error) {
+	return whatsapp.ParseChatPresence(source.Presence)
+}
+
+// Indicates if the account should be online
+func (source *QpPresenceRequest) GetAvailable() (available bool, err error) {
+	switch strings.ToLower(source.Presence) {
+	case "available", "online":
+		return true, nil
+	case "unavailable", "offline":
+		return false, nil
+	default:
+		err = fmt.Errorf("invalid presence: %s, accepted: available, unavailable", source.Presence)
+		return
+	}
+}
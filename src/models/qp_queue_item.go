@@ -0,0 +1,124 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+type QpQueueStatus string
+
+const (
+	// Waiting for delivery or for a new attempt
+	QueuePending QpQueueStatus = "pending"
+
+	// Delivered to whatsapp servers
+	QueueSent QpQueueStatus = "sent"
+
+	// Max attempts reached, will not be delivered
+	QueueFailed QpQueueStatus = "failed"
+
+	// Waiting for the scheduled time, then becomes pending
+	QueueScheduled QpQueueStatus = "scheduled"
+
+	// Canceled before delivery
+	QueueCanceled QpQueueStatus = "canceled"
+
+	// Claimed by the delivery process, cant be canceled anymore
+	QueueSending QpQueueStatus = "sending"
+)
+
+// Outbound message waiting for delivery, stored on database
+type QpQueueItem struct {
+	ID        string        `db:"id" json:"id"`
+	Context   string        `db:"context" json:"wid"`                   // bot that will send this message
+	ChatId    string        `db:"chatid" json:"chatid"`                 // destination
+	MessageId string        `db:"messageid" json:"messageid,omitempty"` // whatsapp message id, generated on enqueue
+	TrackId   string        `db:"trackid" json:"trackid,omitempty"`     // identifier of remote system
+	Status    QpQueueStatus `db:"status" json:"status"`
+	Attempts  uint          `db:"attempts" json:"attempts"`
+	Error     string        `db:"error" json:"error,omitempty"` // last error
+	Payload   []byte        `db:"payload" json:"-"`             // serialized message with attachment content
+	Created   time.Time     `db:"created" json:"created"`
+	Updated   time.Time     `db:"updated" json:"updated"`
+	Next      time.Time     `db:"next" json:"next"` // not before this time, scheduled time if scheduled
+}
+
+// Message serialized on queue, including attachment and original contents that isnt exported on json
+type QpQueuePayload struct {
+	Message  *whatsapp.WhatsappMessage `json:"message"`
+	Content  []byte                    `json:"content,omitempty"`
+	Original []byte                    `json:"original,omitempty"` // original whatsapp message, required for forwarding
+}
+
+// Serializes a message with its attachment and original contents
+func MarshalQueuePayload(msg *whatsapp.WhatsappMessage) (content []byte, err error) {
+	payload := &QpQueuePayload{Message: msg}
+	if msg.Attachment != nil && msg.Attachment.GetContent() != nil {
+		payload.Content = *msg.Attachment.GetContent()
+	}
+
+	payload.Original, err = MarshalWhatsmeowContent(msg.Content)
+	if err != nil {
+		return
+	}
+
+	return json.Marshal(payload)
+}
+
+// Restores a serialized message, with its attachment and original contents
+func UnmarshalQueuePayload(content []byte) (msg *whatsapp.WhatsappMessage, err error) {
+	payload := &QpQueuePayload{}
+	err = json.Unmarshal(content, payload)
+	if err != nil {
+		return
+	}
+
+	msg = payload.Message
+	if msg == nil {
+		err = fmt.Errorf("message missing on payload")
+		return
+	}
+
+	if msg.Attachment != nil && len(payload.Content) > 0 {
+		msg.Attachment.SetContent(&payload.Content)
+	}
+
+	msg.Content, err = UnmarshalWhatsmeowContent(payload.Original)
+	return
+}
+
+func NewQpQueueItem(context string, msg *whatsapp.WhatsappMessage) (item *QpQueueItem, err error) {
+	content, err := MarshalQueuePayload(msg)
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	item = &QpQueueItem{
+		ID:        uuid.New().String(),
+		Context:   context,
+		ChatId:    msg.GetChatId(),
+		MessageId: msg.Id,
+		TrackId:   msg.TrackId,
+		Status:    QueuePending,
+		Payload:   content,
+		Created:   now,
+		Updated:   now,
+		Next:      now,
+	}
+	return
+}
+
+// Indicates that still can be canceled
+func (source *QpQueueItem) IsCancelable() bool {
+	return source.Status == QueuePending || source.Status == QueueScheduled
+}
+
+// Restores the message to send, with its attachment content
+func (source *QpQueueItem) GetMessage() (*whatsapp.WhatsappMessage, error) {
+	return UnmarshalQueuePayload(source.Payload)
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"testing"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestQueuePayloadKeepsForwardContent(t *testing.T) {
+	original := &waProto.Message{
+		DocumentMessage: &waProto.DocumentMessage{
+			Url:      proto.String("https://mmg.whatsapp.net/d/f/test.enc"),
+			FileName: proto.String("report.pdf"),
+			Mimetype: proto.String("application/pdf"),
+		},
+	}
+
+	msg := &whatsapp.WhatsappMessage{
+		Id:         "3EB0FORWARD",
+		Type:       whatsapp.DocumentMessageType,
+		Chat:       whatsapp.WhatsappChat{ID: "5521999990001@s.whatsapp.net"},
+		Attachment: &whatsapp.WhatsappAttachment{FileName: "report.pdf", Mimetype: "application/pdf"},
+		Content:    original,
+		Forwarded:  true,
+	}
+
+	item, err := NewQpQueueItem("5521999990000@s.whatsapp.net", msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := item.GetMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, ok := restored.Content.(*waProto.Message)
+	if !ok || !proto.Equal(content, original) {
+		t.Fatalf("original content not restored: %v", restored.Content)
+	}
+
+	if !restored.Forwarded || restored.Attachment == nil || restored.Attachment.FileName != "report.pdf" {
+		t.Errorf("unexpected restored message: %+v", restored)
+	}
+}
+
+func TestQueuePayloadWithoutContent(t *testing.T) {
+	content := []byte("hello")
+	attachment := &whatsapp.WhatsappAttachment{FileName: "hello.txt"}
+	attachment.SetContent(&content)
+
+	item, err := NewQpQueueItem("5521999990000@s.whatsapp.net", &whatsapp.WhatsappMessage{Type: whatsapp.DocumentMessageType, Attachment: attachment})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := item.GetMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if restored.Content != nil {
+		t.Errorf("unexpected original content: %v", restored.Content)
+	}
+
+	if data := restored.Attachment.GetContent(); data == nil || string(*data) != "hello" {
+		t.Errorf("attachment content not restored")
+	}
+}
@@ -0,0 +1,8 @@
+package models
+
+type QpQueueResponse struct {
+	QpResponse
+	Total uint           `json:"total,omitempty"`
+	Job   *QpQueueItem   `json:"job,omitempty"`  // single item
+	Jobs  []*QpQueueItem `json:"jobs,omitempty"` // filtered items
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type QpQueueSql struct {
+	db *sqlx.DB
+}
+
+func (source QpQueueSql) Find(context string, id string) (*QpQueueItem, error) {
+	var result QpQueueItem
+	err := source.db.Get(&result, "SELECT * FROM queue WHERE context = ? AND id = ?", context, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &result, err
+}
+
+func (source QpQueueSql) FindAll(context string, status QpQueueStatus) ([]*QpQueueItem, error) {
+	result := []*QpQueueItem{}
+	if len(status) == 0 {
+		err := source.db.Select(&result, "SELECT * FROM queue WHERE context = ? ORDER BY created", context)
+		return result, err
+	}
+
+	err := source.db.Select(&result, "SELECT * FROM queue WHERE context = ? AND status = ? ORDER BY created", context, status)
+	return result, err
+}
+
+func (source QpQueueSql) FindPending(context string, limit uint) ([]*QpQueueItem, error) {
+	result := []*QpQueueItem{}
+	err := source.db.Select(&result, "SELECT * FROM queue WHERE context = ? AND status = ? ORDER BY created LIMIT ?", context, QueuePending, limit)
+	return result, err
+}
+
+func (source QpQueueSql) Add(element QpQueueItem) error {
+	query := `INSERT INTO queue (id, context, chatid, messageid, trackid, status, attempts, error, payload, created, updated, next) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := source.db.Exec(query, element.ID, element.Context, element.ChatId, element.MessageId, element.TrackId, element.Status, element.Attempts, element.Error, element.Payload, element.Created, element.Updated, element.Next)
+	return err
+}
+
+func (source QpQueueSql) Update(element QpQueueItem) error {
+	query := `UPDATE queue SET status = ?, attempts = ?, error = ?, payload = ?, updated = ?, next = ? WHERE context = ? AND id = ?`
+	_, err := source.db.Exec(query, element.Status, element.Attempts, element.Error, element.Payload, element.Updated, element.Next, element.Context, element.ID)
+	return err
+}
+
+func (source QpQueueSql) Cancel(context string, id string, updated time.Time) (bool, error) {
+	query := `UPDATE queue SET status = ?, updated = ? WHERE context = ? AND id = ? AND status IN (?, ?)`
+	result, err := source.db.Exec(query, QueueCanceled, updated, context, id, QueuePending, QueueScheduled)
+	return isQueueRowAffected(result, err)
+}
+
+func (source QpQueueSql) Claim(context string, id string, updated time.Time) (bool, error) {
+	query := `UPDATE queue SET status = ?, updated = ? WHERE context = ? AND id = ? AND status = ?`
+	result, err := source.db.Exec(query, QueueSending, updated, context, id, QueuePending)
+	return isQueueRowAffected(result, err)
+}
+
+func (source QpQueueSql) Release(context string) error {
+	query := `UPDATE queue SET status = ?, updated = ? WHERE context = ? AND status = ?`
+	_, err := source.db.Exec(query, QueuePending, time.Now().UTC(), context, QueueSending)
+	return err
+}
+
+func isQueueRowAffected(result sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+func (source QpQueueSql) Promote(context string, until time.Time) error {
+	query := `UPDATE queue SET status = ?, updated = ? WHERE context = ? AND status = ? AND next <= ?`
+	_, err := source.db.Exec(query, QueuePending, time.Now().UTC(), context, QueueScheduled, until)
+	return err
+}
+
+func (source QpQueueSql) Purge(context string, before time.Time) error {
+	query := `DELETE FROM queue WHERE context = ? AND status NOT IN (?, ?, ?) AND updated < ?`
+	_, err := source.db.Exec(query, context, QueuePending, QueueScheduled, QueueSending, before)
+	return err
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+const testQueueContext = "5521999990000@s.whatsapp.net"
+
+func newTestQueueSql(t *testing.T) QpQueueSql {
+	db, err := sqlx.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open error: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec("CREATE TABLE queue (`id` VARCHAR (100) PRIMARY KEY UNIQUE NOT NULL, `context` VARCHAR (255) NOT NULL, `chatid` VARCHAR (255) NOT NULL, `messageid` VARCHAR (255) NOT NULL DEFAULT '', `trackid` VARCHAR (100) NOT NULL DEFAULT '', `status` VARCHAR (20) NOT NULL DEFAULT 'pending', `attempts` INTEGER NOT NULL DEFAULT 0, `error` TEXT NOT NULL DEFAULT '', `payload` BLOB NOT NULL, `created` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, `updated` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, `next` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP)")
+	if err != nil {
+		t.Fatalf("schema error: %s", err)
+	}
+	return QpQueueSql{db: db}
+}
+
+func addTestQueueItem(t *testing.T, source QpQueueSql) *QpQueueItem {
+	item, err := NewQpQueueItem(testQueueContext, &whatsapp.WhatsappMessage{Id: "3EB0QUEUE", Text: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = source.Add(*item); err != nil {
+		t.Fatal(err)
+	}
+	return item
+}
+
+func TestQueueSqlCancelAfterClaim(t *testing.T) {
+	source := newTestQueueSql(t)
+	item := addTestQueueItem(t, source)
+
+	claimed, err := source.Claim(testQueueContext, item.ID, time.Now().UTC())
+	if err != nil || !claimed {
+		t.Fatalf("expected claim, got: %v, %v", claimed, err)
+	}
+
+	canceled, err := source.Cancel(testQueueContext, item.ID, time.Now().UTC())
+	if err != nil || canceled {
+		t.Fatalf("claimed item should not be canceled: %v, %v", canceled, err)
+	}
+
+	// interrupted on a restart, pending again
+	if err = source.Release(testQueueContext); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _ := source.Find(testQueueContext, item.ID)
+	if stored == nil || stored.Status != QueuePending {
+		t.Fatalf("expected pending after release, got: %v", stored)
+	}
+}
+
+func TestQueueSqlClaimAfterCancel(t *testing.T) {
+	source := newTestQueueSql(t)
+	item := addTestQueueItem(t, source)
+
+	canceled, err := source.Cancel(testQueueContext, item.ID, time.Now().UTC())
+	if err != nil || !canceled {
+		t.Fatalf("expected cancel, got: %v, %v", canceled, err)
+	}
+
+	claimed, err := source.Claim(testQueueContext, item.ID, time.Now().UTC())
+	if err != nil || claimed {
+		t.Fatalf("canceled item should not be claimed: %v, %v", claimed, err)
+	}
+
+	// unknown items are never canceled
+	canceled, _ = source.Cancel(testQueueContext, "missing", time.Now().UTC())
+	if canceled {
+		t.Error("unknown item canceled")
+	}
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"fmt"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+/*
+<summary>
+	Request to react to a previous message
+	An empty reaction removes the current one
+</summary>
+*/
+type QpReactionRequest struct {
+	// (Optional) Chat of the message, retrieved from cache if empty
+	ChatId string `json:"chatId,omitempty"`
+
+	// Message that will receive the reaction
+	MessageId string `json:"messageId"`
+
+	// Emoji of the reaction, empty to remove
+	Reaction string `json:"reaction"`
+
+	// (Optional) TrackId - less priority (urlparam -> query -> header -> body)
+	TrackId string `json:"trackId,omitempty"`
+}
+
+func (source *QpReactionRequest) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage, err error) {
+	if len(source.MessageId) == 0 {
+		err = fmt.Errorf("message id missing")
+		return
+	}
+
+	chatId, err := whatsapp.FormatEndpoint(source.ChatId)
+	if err != nil {
+		return
+	}
+
+	chat := whatsapp.WhatsappChat{ID: chatId}
+	msg = &whatsapp.WhatsappMessage{
+		TrackId:      source.TrackId,
+		Type:         whatsapp.ReactionMessageType,
+		Text:         source.Reaction,
+		InReply:      source.MessageId,
+		Chat:         chat,
+		FromMe:       true,
+		FromInternal: true,
+	}
+	return
+}
@@ -0,0 +1,27 @@
+package models
+
+/*
+<summary>
+	Request to send read receipts for received messages of a chat
+	Chat id is optional if all messages are on cache
+</summary>
+*/
+type QpReadRequest struct {
+	ChatId     string   `json:"chatId,omitempty"`
+	MessageId  string   `json:"messageId,omitempty"`
+	MessageIds []string `json:"messageIds,omitempty"`
+}
+
+// All message ids of this request, single or list
+func (source *QpReadRequest) GetMessageIds() (ids []string) {
+	if len(source.MessageId) > 0 {
+		ids = append(ids, source.MessageId)
+	}
+
+	for _, id := range source.MessageIds {
+		if len(id) > 0 {
+			ids = append(ids, id)
+		}
+	}
+	return
+}
@@ -0,0 +1,235 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+/*
+<summary>
+	Filters and pagination of received messages, newest first
+	Cursor is the position after the last message of the previous page
+</summary>
+*/
+type QpReceiveFilter struct {
+	ChatId       string
+	Type         *whatsapp.WhatsappMessageType
+	FromMe       *bool
+	FromInternal *bool
+	TrackId      string
+	Search       string    // case insensitive text search
+	After        time.Time // lower bound, exclusive
+	Before       time.Time // upper bound, exclusive, zero means no limit
+	Cursor       string
+	Limit        uint // zero or above MessagesDefaultQueryLimit means that max
+}
+
+// Page size, never above the max messages restored from database
+func (source *QpReceiveFilter) GetLimit() uint {
+	if source.Limit > 0 && source.Limit < MessagesDefaultQueryLimit {
+		return source.Limit
+	}
+	return MessagesDefaultQueryLimit
+}
+
+/*
+<summary>
+	Reads filters from url query parameters
+	?timestamp={unix} ?until={unix} ?chatid= ?type={text|image|...} ?fromme={bool} ?frominternal={bool}
+	?trackid= ?search= ?cursor= ?limit=
+</summary>
+*/
+func ParseReceiveFilter(query url.Values) (filter *QpReceiveFilter, err error) {
+	filter = &QpReceiveFilter{
+		TrackId: query.Get("trackid"),
+		Search:  strings.TrimSpace(query.Get("search")),
+		Cursor:  query.Get("cursor"),
+	}
+
+	if chatId := query.Get("chatid"); len(chatId) > 0 {
+		filter.ChatId, err = whatsapp.FormatEndpoint(chatId)
+		if err != nil {
+			return
+		}
+	}
+
+	if value := query.Get("type"); len(value) > 0 {
+		Type, err := whatsapp.ParseMessageType(value)
+		if err != nil {
+			return filter, err
+		}
+		filter.Type = &Type
+	}
+
+	filter.FromMe, err = parseOptionalBool(query, "fromme")
+	if err != nil {
+		return
+	}
+
+	filter.FromInternal, err = parseOptionalBool(query, "frominternal")
+	if err != nil {
+		return
+	}
+
+	filter.After, err = parseOptionalUnix(query, "timestamp")
+	if err != nil {
+		return
+	}
+
+	filter.Before, err = parseOptionalUnix(query, "until")
+	if err != nil {
+		return
+	}
+
+	if value := query.Get("limit"); len(value) > 0 {
+		limit, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return filter, fmt.Errorf("invalid limit: %s", value)
+		}
+		filter.Limit = uint(limit)
+	}
+	return
+}
+
+func (source *QpReceiveFilter) Match(msg *whatsapp.WhatsappMessage) bool {
+	if len(source.ChatId) > 0 && msg.Chat.ID != source.ChatId {
+		return false
+	}
+
+	if source.Type != nil && msg.Type != *source.Type {
+		return false
+	}
+
+	if source.FromMe != nil && msg.FromMe != *source.FromMe {
+		return false
+	}
+
+	if source.FromInternal != nil && msg.FromInternal != *source.FromInternal {
+		return false
+	}
+
+	if len(source.TrackId) > 0 && msg.TrackId != source.TrackId {
+		return false
+	}
+
+	if !source.Before.IsZero() && !msg.Timestamp.Before(source.Before) {
+		return false
+	}
+
+	if len(source.Search) > 0 && !strings.Contains(strings.ToLower(msg.Text), strings.ToLower(source.Search)) {
+		return false
+	}
+	return true
+}
+
+/*
+<summary>
+	Filters, sorts (newest first) and paginates messages
+	Returns the cursor of the next page, empty if there is no more messages
+</summary>
+*/
+func (source *QpReceiveFilter) Apply(messages []whatsapp.WhatsappMessage) (page []whatsapp.WhatsappMessage, next string, err error) {
+	matched := []whatsapp.WhatsappMessage{}
+	for index := range messages {
+		if source.Match(&messages[index]) {
+			matched = append(matched, messages[index])
+		}
+	}
+
+	// same timestamp are ordered by id, keeping pages stable
+	sort.SliceStable(matched, func(i, j int) bool {
+		return IsReceiveOrderBefore(&matched[i], &matched[j])
+	})
+
+	start := 0
+	if len(source.Cursor) > 0 {
+		timestamp, id, err := DecodeReceiveCursor(source.Cursor)
+		if err != nil {
+			return page, next, err
+		}
+
+		cursor := &whatsapp.WhatsappMessage{Id: id, Timestamp: timestamp}
+		start = sort.Search(len(matched), func(i int) bool {
+			return IsReceiveOrderBefore(cursor, &matched[i])
+		})
+	}
+
+	page = matched[start:]
+	if limit := source.GetLimit(); uint(len(page)) > limit {
+		page = page[:limit]
+		next = EncodeReceiveCursor(&page[len(page)-1])
+	}
+	return
+}
+
+// Newest first, then by id
+func IsReceiveOrderBefore(a *whatsapp.WhatsappMessage, b *whatsapp.WhatsappMessage) bool {
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.After(b.Timestamp)
+	}
+	return a.Id < b.Id
+}
+
+func EncodeReceiveCursor(msg *whatsapp.WhatsappMessage) string {
+	value := fmt.Sprintf("%d:%s", msg.Timestamp.UnixNano(), msg.Id)
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+func DecodeReceiveCursor(cursor string) (timestamp time.Time, id string, err error) {
+	value, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		err = fmt.Errorf("invalid cursor: %s", cursor)
+		return
+	}
+
+	parts := strings.SplitN(string(value), ":", 2)
+	if len(parts) != 2 {
+		err = fmt.Errorf("invalid cursor: %s", cursor)
+		return
+	}
+
+	nano, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		err = fmt.Errorf("invalid cursor: %s", cursor)
+		return
+	}
+
+	return time.Unix(0, nano), parts[1], nil
+}
+
+func parseOptionalBool(query url.Values, key string) (*bool, error) {
+	if !query.Has(key) {
+		return nil, nil
+	}
+
+	value, err := strconv.ParseBool(query.Get(key))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", key, query.Get(key))
+	}
+	return &value, nil
+}
+
+func parseOptionalUnix(query url.Values, key string) (result time.Time, err error) {
+	value := query.Get(key)
+	if len(value) == 0 {
+		return
+	}
+
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		err = fmt.Errorf("invalid %s: %s", key, value)
+		return
+	}
+
+	if unix > 0 {
+		result = time.Unix(unix, 0)
+	}
+	return
+}
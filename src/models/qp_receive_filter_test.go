@@ -0,0 +1,101 @@
+package models
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+func parseTestFilter(t *testing.T, query string) *QpReceiveFilter {
+	t.Helper()
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter, err := ParseReceiveFilter(values)
+	if err != nil {
+		t.Fatalf("%q: unexpected error: %s", query, err)
+	}
+	return filter
+}
+
+func TestParseReceiveFilter(t *testing.T) {
+	filter := parseTestFilter(t, "")
+	if !filter.After.IsZero() || filter.Limit != 0 || filter.FromMe != nil || filter.Type != nil {
+		t.Errorf("empty query should not filter: %+v", filter)
+	}
+
+	filter = parseTestFilter(t, "timestamp=1666000000&until=1666000100&limit=10")
+	if !filter.After.Equal(time.Unix(1666000000, 0)) || !filter.Before.Equal(time.Unix(1666000100, 0)) || filter.Limit != 10 {
+		t.Errorf("unexpected bounds: %+v", filter)
+	}
+
+	filter = parseTestFilter(t, "chatid=5521999990001&type=image&trackid=crm&search=%20Hello%20")
+	if filter.ChatId != "5521999990001@s.whatsapp.net" || filter.TrackId != "crm" || filter.Search != "Hello" {
+		t.Errorf("unexpected fields: %+v", filter)
+	}
+
+	if filter.Type == nil || *filter.Type != whatsapp.ImageMessageType {
+		t.Errorf("expected image type, got: %v", filter.Type)
+	}
+
+	filter = parseTestFilter(t, "fromme=true&frominternal=0")
+	if filter.FromMe == nil || !*filter.FromMe || filter.FromInternal == nil || *filter.FromInternal {
+		t.Errorf("unexpected booleans: %+v", filter)
+	}
+}
+
+func TestParseReceiveFilterInvalid(t *testing.T) {
+	for _, query := range []string{"type=unknowntype", "fromme=maybe", "timestamp=yesterday", "until=-", "limit=-1"} {
+		values, _ := url.ParseQuery(query)
+		if _, err := ParseReceiveFilter(values); err == nil {
+			t.Errorf("%q: expected error", query)
+		}
+	}
+}
+
+func TestReceiveCursorRoundTrip(t *testing.T) {
+	for _, msg := range []whatsapp.WhatsappMessage{
+		{Id: "3EB0ABC", Timestamp: time.Unix(1666000000, 0)},
+		{Id: "3EB0ABC", Timestamp: time.Unix(1666000000, 123456789)},
+		{Id: "A:B", Timestamp: time.Unix(1, 0)}, // separator inside id
+	} {
+		timestamp, id, err := DecodeReceiveCursor(EncodeReceiveCursor(&msg))
+		if err != nil || !timestamp.Equal(msg.Timestamp) || id != msg.Id {
+			t.Errorf("round trip of %s differs: %v %s %v", msg.Id, timestamp, id, err)
+		}
+	}
+
+	for _, invalid := range []string{"!!", "bm9zZXBhcmF0b3I", "eDpBQkM"} {
+		if _, _, err := DecodeReceiveCursor(invalid); err == nil {
+			t.Errorf("%q: expected error", invalid)
+		}
+	}
+}
+
+func TestReceiveFilterApplyPages(t *testing.T) {
+	base := time.Unix(1666000000, 0)
+	messages := []whatsapp.WhatsappMessage{
+		{Id: "A", Timestamp: base.Add(1 * time.Second)},
+		{Id: "B", Timestamp: base.Add(3 * time.Second)},
+		{Id: "C", Timestamp: base.Add(2 * time.Second)},
+		{Id: "D", Timestamp: base.Add(3 * time.Second)},
+	}
+
+	// newest first, same timestamp ordered by id
+	filter := &QpReceiveFilter{Limit: 2}
+	page, next, err := filter.Apply(messages)
+	if err != nil || len(page) != 2 || page[0].Id != "B" || page[1].Id != "D" || len(next) == 0 {
+		t.Fatalf("unexpected first page: %v, %s, %v", page, next, err)
+	}
+
+	filter.Cursor = next
+	page, next, err = filter.Apply(messages)
+	if err != nil || len(page) != 2 || page[0].Id != "C" || page[1].Id != "A" || len(next) != 0 {
+		t.Fatalf("unexpected last page: %v, %s, %v", page, next, err)
+	}
+}
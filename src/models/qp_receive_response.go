@@ -8,5 +8,6 @@ type QpReceiveResponse struct {
 	QpResponse
 	Total    uint64                     `json:"total"`
 	Messages []whatsapp.WhatsappMessage `json:"messages,omitempty"`
+	Next     string                     `json:"next,omitempty"` // cursor of the next page, if limited
 	Bot      QPBot                      `json:"bot"`
 }
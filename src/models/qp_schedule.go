@@ -0,0 +1,65 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Accepted layouts for local schedule times, without offset
+var ScheduleLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+var regexUnixTimestamp = regexp.MustCompile(`^\d+$`)
+
+/*
+<summary>
+	Parses a schedule time from unix timestamp, RFC3339 or local date time
+	Local date times are interpreted on the informed IANA timezone, UTC if empty
+</summary>
+*/
+func ParseScheduleTime(value string, timezone string) (result time.Time, err error) {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 {
+		err = fmt.Errorf("empty schedule time")
+		return
+	}
+
+	if regexUnixTimestamp.MatchString(value) {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return result, err
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+
+	result, err = time.Parse(time.RFC3339, value)
+	if err == nil {
+		return result.UTC(), nil
+	}
+
+	location := time.UTC
+	if len(timezone) > 0 {
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			err = fmt.Errorf("invalid timezone: %s, %s", timezone, err)
+			return
+		}
+	}
+
+	for _, layout := range ScheduleLayouts {
+		result, err = time.ParseInLocation(layout, value, location)
+		if err == nil {
+			return result.UTC(), nil
+		}
+	}
+
+	err = fmt.Errorf("invalid schedule time: %s, use unix timestamp, RFC3339 or yyyy-MM-ddTHH:mm:ss with timezone", value)
+	return
+}
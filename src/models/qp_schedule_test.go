@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleTimeAccepted(t *testing.T) {
+	expected := map[string]time.Time{
+		"1666000000":                time.Unix(1666000000, 0),
+		"  1666000000 ":             time.Unix(1666000000, 0),
+		"2022-10-20T10:00:00-03:00": time.Date(2022, 10, 20, 13, 0, 0, 0, time.UTC),
+		"2022-10-20T10:00:00":       time.Date(2022, 10, 20, 10, 0, 0, 0, time.UTC),
+		"2022-10-20 10:00":          time.Date(2022, 10, 20, 10, 0, 0, 0, time.UTC),
+	}
+
+	for value, when := range expected {
+		result, err := ParseScheduleTime(value, "")
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", value, err)
+			continue
+		}
+
+		if !result.Equal(when) || result.Location() != time.UTC {
+			t.Errorf("%q: expected %v, got %v", value, when, result)
+		}
+	}
+}
+
+func TestParseScheduleTimeTimezone(t *testing.T) {
+	result, err := ParseScheduleTime("2022-10-20T10:00", "America/Sao_Paulo")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if when := time.Date(2022, 10, 20, 13, 0, 0, 0, time.UTC); !result.Equal(when) {
+		t.Errorf("expected %v, got %v", when, result)
+	}
+
+	// offsets on value wins over the informed timezone
+	result, err = ParseScheduleTime("2022-10-20T10:00:00Z", "America/Sao_Paulo")
+	if err != nil || result.Hour() != 10 {
+		t.Errorf("RFC3339 should ignore timezone, got: %v, %v", result, err)
+	}
+
+	if _, err = ParseScheduleTime("2022-10-20T10:00", "Mars/Olympus"); err == nil {
+		t.Error("accepted an invalid timezone")
+	}
+}
+
+func TestParseScheduleTimeRejected(t *testing.T) {
+	for _, value := range []string{"", " ", "tomorrow", "20/10/2022 10:00", "2022-10-20"} {
+		if result, err := ParseScheduleTime(value, ""); err == nil {
+			t.Errorf("%q: expected error, got %v", value, result)
+		}
+	}
+}
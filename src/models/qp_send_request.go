@@ -23,9 +23,24 @@ type QpSendRequest struct {
 
 	Text string `json:"text,omitempty"`
 
+	// (Optional) Message id to quote, reply to
+	InReply string `json:"inReply,omitempty"`
+
 	// (Optional) Sugested filename on user download
 	FileName string `json:"fileName,omitempty"`
 
+	// (Optional) Audio as voice note (true) or as audio file (false), default by mime type
+	Voice *bool `json:"voice,omitempty"`
+
+	// (Optional) Max milliseconds showing typing indicator before sending, proportional to text length
+	TypingDelay uint `json:"typingDelay,omitempty"`
+
+	// (Optional) Users to mention (@), phones or ids, @phone tokens on text are mentioned too
+	Mentions []string `json:"mentions,omitempty"`
+
+	// (Optional) Mention every participant, only on groups
+	MentionAll bool `json:"mentionAll,omitempty"`
+
 	Content []byte
 }
 
@@ -66,6 +81,10 @@ func (source *QpSendRequest) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage,
 		Id:           source.Id,
 		TrackId:      source.TrackId,
 		Text:         source.Text,
+		InReply:      source.InReply,
+		TypingDelay:  source.TypingDelay,
+		Mentions:     source.Mentions,
+		MentionAll:   source.MentionAll,
 		Chat:         chat,
 		FromMe:       true,
 		FromInternal: true,
@@ -111,5 +130,21 @@ func (source *QpSendRequest) ToWhatsappAttachment() (attach *whatsapp.WhatsappAt
 	attach.FileLength = uint64(len(source.Content))
 	attach.Mimetype = mimeType
 	attach.SetContent(&source.Content)
+
+	// ogg audios are voice notes by default
+	attach.Ptt = whatsapp.IsVoiceMimetype(mimeType)
+	if source.Voice != nil {
+		attach.Ptt = *source.Voice
+	}
+
+	// transcoding to a true voice note, with duration and waveform
+	if attach.Ptt {
+		err = whatsapp.ConvertToVoice(attach)
+		if err != nil {
+			log.Warnf("audio not converted to voice note, sending original: %s", err)
+			attach.Ptt = whatsapp.IsVoiceMimetype(mimeType)
+			err = nil
+		}
+	}
 	return
 }
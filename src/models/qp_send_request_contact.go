@@ -0,0 +1,56 @@
+package models
+
+import (
+	"fmt"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+type QpSendRequestContact struct {
+	QpSendRequest
+
+	// Single contact card
+	Contact *whatsapp.WhatsappContact `json:"contact,omitempty"`
+
+	// Multiple contact cards, sent as one message
+	Contacts []whatsapp.WhatsappContact `json:"contacts,omitempty"`
+}
+
+// Joins single and multiple contacts
+func (source *QpSendRequestContact) GetContacts() (contacts []whatsapp.WhatsappContact) {
+	if source.Contact != nil {
+		contacts = append(contacts, *source.Contact)
+	}
+
+	contacts = append(contacts, source.Contacts...)
+	return
+}
+
+// Checks if there is at least one valid contact to send
+func (source *QpSendRequestContact) Validate() error {
+	contacts := source.GetContacts()
+	if len(contacts) == 0 {
+		return fmt.Errorf("contact not found, do not send empty messages")
+	}
+
+	for _, contact := range contacts {
+		err := contact.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (source *QpSendRequestContact) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage, err error) {
+	msg, err = source.QpSendRequest.ToWhatsappMessage()
+	if err != nil {
+		return
+	}
+
+	msg.Type = whatsapp.ContactMessageType
+	msg.Text = ""
+	msg.Contacts = source.GetContacts()
+	return
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"fmt"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+type QpSendRequestLocation struct {
+	QpSendRequest
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+
+	// (Optional) Title of the place
+	Name string `json:"name,omitempty"`
+
+	// (Optional) Readable address of the place
+	Address string `json:"address,omitempty"`
+
+	// (Optional) Public url with more information about the place
+	Url string `json:"url,omitempty"`
+}
+
+// Checks if coordinates are present and in valid ranges
+func (source *QpSendRequestLocation) Validate() error {
+	if source.Latitude == nil {
+		return fmt.Errorf("latitude missing")
+	}
+
+	if source.Longitude == nil {
+		return fmt.Errorf("longitude missing")
+	}
+
+	if *source.Latitude < -90 || *source.Latitude > 90 {
+		return fmt.Errorf("invalid latitude: %v, must be between -90 and 90", *source.Latitude)
+	}
+
+	if *source.Longitude < -180 || *source.Longitude > 180 {
+		return fmt.Errorf("invalid longitude: %v, must be between -180 and 180", *source.Longitude)
+	}
+
+	return nil
+}
+
+func (source *QpSendRequestLocation) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage, err error) {
+	err = source.Validate()
+	if err != nil {
+		return
+	}
+
+	msg, err = source.QpSendRequest.ToWhatsappMessage()
+	if err != nil {
+		return
+	}
+
+	msg.Type = whatsapp.LocationMessageType
+	msg.Text = ""
+	msg.Location = &whatsapp.WhatsappLocation{
+		Latitude:  *source.Latitude,
+		Longitude: *source.Longitude,
+		Name:      source.Name,
+		Address:   source.Address,
+		Url:       source.Url,
+	}
+	return
+}
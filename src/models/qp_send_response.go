@@ -9,3 +9,13 @@ func (source *QpSendResponse) ParseSuccess(message *QpSendResponseMessage) {
 	source.QpResponse.ParseSuccess("sended with success")
 	source.Message = message
 }
+
+func (source *QpSendResponse) ParseScheduled(message *QpSendResponseMessage) {
+	source.QpResponse.ParseSuccess("scheduled with success")
+	source.Message = message
+}
+
+func (source *QpSendResponse) ParseQueued(message *QpSendResponseMessage) {
+	source.QpResponse.ParseSuccess("queued with success")
+	source.Message = message
+}
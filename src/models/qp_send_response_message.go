@@ -1,8 +1,16 @@
 package models
 
+import "time"
+
 type QpSendResponseMessage struct {
 	Id      string `json:"id,omitempty"`
 	Wid     string `json:"wid,omitempty"`
 	ChatId  string `json:"chatId,omitempty"`
 	TrackId string `json:"trackId,omitempty"`
+
+	// Queue job, when stored for later delivery
+	JobId string `json:"jobId,omitempty"`
+
+	// Delivery time in UTC, when scheduled
+	Schedule *time.Time `json:"schedule,omitempty"`
 }
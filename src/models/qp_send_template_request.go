@@ -0,0 +1,28 @@
+package models
+
+/*
+<summary>
+	Request to send a stored template
+	Template by id or name, placeholders filled with variables
+</summary>
+*/
+type QpSendTemplateRequest struct {
+	QpSendAnyRequest
+	Template  string      `json:"template"`
+	Variables QpVariables `json:"variables,omitempty"`
+}
+
+// Fills text, url and filename from the rendered template
+func (source *QpSendTemplateRequest) Render(template *QpTemplate) (err error) {
+	text, url, err := template.Render(source.Variables)
+	if err != nil {
+		return
+	}
+
+	source.Text = text
+	source.Url = url
+	if len(source.FileName) == 0 {
+		source.FileName = template.FileName
+	}
+	return
+}
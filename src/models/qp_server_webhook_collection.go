@@ -48,6 +48,7 @@ func (source *QpServerWebhookCollection) WebhookAdd(webhook *QpWebhook) (affecte
 	if botWHook != nil {
 		botWHook.ForwardInternal = webhook.ForwardInternal
 		botWHook.TrackId = webhook.TrackId
+		botWHook.History = webhook.History
 		botWHook.Extra = webhook.Extra
 		err = source.db.Update(*botWHook)
 		if err != nil {
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"fmt"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+/*
+<summary>
+	Request to post a status (story), text with style or image/video with caption
+	Custom audiences are not supported, recipients are always resolved from the account status privacy
+</summary>
+*/
+type QpStatusRequest struct {
+	QpSendAnyRequest
+
+	// (Optional) Text status background, #RRGGBB or #AARRGGBB
+	BackgroundColor string `json:"backgroundColor,omitempty"`
+
+	// (Optional) Text status font color, #RRGGBB or #AARRGGBB
+	TextColor string `json:"textColor,omitempty"`
+
+	// (Optional) Text status font name
+	Font string `json:"font,omitempty"`
+}
+
+func (source *QpStatusRequest) ToWhatsappMessage() (msg *whatsapp.WhatsappMessage, err error) {
+	if len(source.Url) > 0 {
+		err = source.GenerateUrlContent()
+	} else if len(source.Content) > 0 {
+		err = source.GenerateEmbbedContent()
+	}
+
+	if err != nil {
+		return
+	}
+
+	msg = &whatsapp.WhatsappMessage{
+		Id:           source.Id,
+		TrackId:      source.TrackId,
+		Text:         source.Text,
+		Chat:         whatsapp.WhatsappChat{ID: whatsapp.StatusBroadcastChatId},
+		FromMe:       true,
+		FromInternal: true,
+		Type:         whatsapp.TextMessageType,
+	}
+
+	if len(source.QpSendRequest.Content) > 0 {
+		attach, err := source.ToWhatsappAttachment()
+		if err != nil {
+			return msg, err
+		}
+
+		msg.Attachment = attach
+		msg.Type = whatsapp.GetMessageType(attach.Mimetype)
+		if msg.Type != whatsapp.ImageMessageType && msg.Type != whatsapp.VideoMessageType {
+			return msg, fmt.Errorf("status accepts only image or video attachments, received: %s", attach.Mimetype)
+		}
+		return msg, nil
+	}
+
+	if len(msg.Text) == 0 {
+		err = fmt.Errorf("text not found, do not post empty status")
+		return
+	}
+
+	story := &whatsapp.WhatsappStory{
+		BackgroundColor: source.BackgroundColor,
+		TextColor:       source.TextColor,
+		Font:            source.Font,
+	}
+
+	err = story.Validate()
+	if err != nil {
+		return
+	}
+
+	msg.Story = story
+	return
+}
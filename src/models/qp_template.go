@@ -0,0 +1,67 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reusable message, text with {{placeholders}} and optional attachment url
+type QpTemplate struct {
+	ID       string    `db:"id" json:"id"`
+	UserID   string    `db:"user_id" json:"-"`
+	Context  string    `db:"context" json:"wid,omitempty"` // bot restriction, empty for all bots of the user
+	Name     string    `db:"name" json:"name"`
+	Text     string    `db:"text" json:"text,omitempty"`
+	Url      string    `db:"url" json:"url,omitempty"`           // attachment, also accepts placeholders
+	FileName string    `db:"filename" json:"fileName,omitempty"` // sugested filename of attachment
+	Created  time.Time `db:"created" json:"created"`
+	Updated  time.Time `db:"updated" json:"updated"`
+}
+
+func NewQpTemplate(userId string) *QpTemplate {
+	now := time.Now().UTC()
+	return &QpTemplate{
+		ID:      uuid.New().String(),
+		UserID:  userId,
+		Created: now,
+		Updated: now,
+	}
+}
+
+func (source *QpTemplate) Validate() error {
+	source.Name = strings.TrimSpace(source.Name)
+	if len(source.Name) == 0 {
+		return fmt.Errorf("template name missing")
+	}
+
+	if len(source.Text) == 0 && len(source.Url) == 0 {
+		return fmt.Errorf("template text or url missing")
+	}
+	return nil
+}
+
+// Available for this bot
+func (source *QpTemplate) IsAvailable(context string) bool {
+	return len(source.Context) == 0 || source.Context == context
+}
+
+// Text and url with placeholders replaced, fails if any variable is missing
+func (source *QpTemplate) Render(variables QpVariables) (text string, url string, err error) {
+	text, missing := ReplaceVariables(source.Text, variables)
+	url, missingOnUrl := ReplaceVariables(source.Url, variables)
+	missing = append(missing, missingOnUrl...)
+	if len(missing) > 0 {
+		err = fmt.Errorf("missing variables for template %s: %s", source.Name, strings.Join(missing, ", "))
+	}
+	return
+}
+
+// All placeholders used by this template
+func (source *QpTemplate) GetPlaceholders() []string {
+	_, onText := ReplaceVariables(source.Text, nil)
+	_, onUrl := ReplaceVariables(source.Url, nil)
+	return append(onText, onUrl...)
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type QpTemplateSql struct {
+	db *sqlx.DB
+}
+
+func (source QpTemplateSql) Find(userId string, id string) (*QpTemplate, error) {
+	var result QpTemplate
+	err := source.db.Get(&result, "SELECT * FROM templates WHERE user_id = ? AND id = ?", userId, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &result, err
+}
+
+func (source QpTemplateSql) FindByName(userId string, context string, name string) (*QpTemplate, error) {
+	var result QpTemplate
+	err := source.db.Get(&result, "SELECT * FROM templates WHERE user_id = ? AND context = ? AND name = ?", userId, context, name)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &result, err
+}
+
+func (source QpTemplateSql) FindAll(userId string, context string) ([]*QpTemplate, error) {
+	result := []*QpTemplate{}
+	if len(context) == 0 {
+		err := source.db.Select(&result, "SELECT * FROM templates WHERE user_id = ? ORDER BY name", userId)
+		return result, err
+	}
+
+	err := source.db.Select(&result, "SELECT * FROM templates WHERE user_id = ? AND (context = '' OR context = ?) ORDER BY name", userId, context)
+	return result, err
+}
+
+func (source QpTemplateSql) Add(element QpTemplate) error {
+	query := `INSERT INTO templates (id, user_id, context, name, text, url, filename, created, updated) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := source.db.Exec(query, element.ID, element.UserID, element.Context, element.Name, element.Text, element.Url, element.FileName, element.Created, element.Updated)
+	return err
+}
+
+func (source QpTemplateSql) Update(element QpTemplate) error {
+	query := `UPDATE templates SET context = ?, name = ?, text = ?, url = ?, filename = ?, updated = ? WHERE user_id = ? AND id = ?`
+	_, err := source.db.Exec(query, element.Context, element.Name, element.Text, element.Url, element.FileName, element.Updated, element.UserID, element.ID)
+	return err
+}
+
+func (source QpTemplateSql) Delete(userId string, id string) error {
+	_, err := source.db.Exec("DELETE FROM templates WHERE user_id = ? AND id = ?", userId, id)
+	return err
+}
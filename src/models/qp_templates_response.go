@@ -0,0 +1,7 @@
+package models
+
+type QpTemplatesResponse struct {
+	QpResponse
+	Total     uint          `json:"total,omitempty"`
+	Templates []*QpTemplate `json:"templates,omitempty"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Named values used to fill {{placeholders}} on texts, stored as json on database
+type QpVariables map[string]string
+
+// Implements driver.Valuer, empty text if no variables
+func (source QpVariables) Value() (driver.Value, error) {
+	if len(source) == 0 {
+		return "", nil
+	}
+
+	content, err := json.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+	return string(content), nil
+}
+
+// Implements sql.Scanner
+func (source *QpVariables) Scan(value interface{}) error {
+	var content []byte
+	switch v := value.(type) {
+	case nil:
+		*source = nil
+		return nil
+	case string:
+		content = []byte(v)
+	case []byte:
+		content = v
+	default:
+		return fmt.Errorf("invalid type for variables: %T", value)
+	}
+
+	if len(content) == 0 {
+		*source = nil
+		return nil
+	}
+
+	return json.Unmarshal(content, source)
+}
+
+var regexPlaceholder = regexp.MustCompile(`{{\s*([^{}\s]+)\s*}}`)
+
+/*
+<summary>
+	Replaces {{placeholders}} on text with the respective variables, names are case insensitive
+	Placeholders without a variable remains untouched and are returned as missing
+</summary>
+*/
+func ReplaceVariables(text string, variables QpVariables) (result string, missing []string) {
+	normalized := make(map[string]string, len(variables))
+	for name, value := range variables {
+		normalized[strings.ToLower(name)] = value
+	}
+
+	result = regexPlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		name := regexPlaceholder.FindStringSubmatch(placeholder)[1]
+		if value, ok := normalized[strings.ToLower(name)]; ok {
+			return value
+		}
+
+		missing = append(missing, name)
+		return placeholder
+	})
+	return
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReplaceVariables(t *testing.T) {
+	cases := []struct {
+		name      string
+		text      string
+		variables QpVariables
+		result    string
+		missing   []string
+	}{
+		{name: "no placeholders", text: "hello", variables: QpVariables{"name": "john"}, result: "hello"},
+		{name: "single", text: "hello {{name}}", variables: QpVariables{"name": "john"}, result: "hello john"},
+		{name: "case insensitive", text: "hello {{ Name }}", variables: QpVariables{"NAME": "john"}, result: "hello john"},
+		{name: "repeated", text: "{{a}}-{{a}}", variables: QpVariables{"a": "x"}, result: "x-x"},
+		{name: "missing", text: "hi {{name}}, code {{code}}", variables: QpVariables{"name": "ana"}, result: "hi ana, code {{code}}", missing: []string{"code"}},
+		{name: "without variables", text: "{{name}}", result: "{{name}}", missing: []string{"name"}},
+		{name: "empty value", text: "[{{name}}]", variables: QpVariables{"name": ""}, result: "[]"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, missing := ReplaceVariables(c.text, c.variables)
+			if result != c.result {
+				t.Errorf("expected: %q, got: %q", c.result, result)
+			}
+
+			if !reflect.DeepEqual(missing, c.missing) {
+				t.Errorf("expected missing: %v, got: %v", c.missing, missing)
+			}
+		})
+	}
+}
@@ -15,6 +15,7 @@ type QpWebhook struct {
 	Url             string      `db:"url" json:"url,omitempty"`                         // destination
 	ForwardInternal bool        `db:"forwardinternal" json:"forwardinternal,omitempty"` // forward internal msg from api
 	TrackId         string      `db:"trackid" json:"trackid,omitempty"`                 // identifier of remote system to avoid loop
+	History         bool        `db:"history" json:"history,omitempty"`                 // forward messages from history sync
 	Extra           interface{} `db:"extra" json:"extra,omitempty"`                     // extra info to append on payload
 	Failure         *time.Time  `json:"failure,omitempty"`                              // first failure timestamp
 	Success         *time.Time  `json:"success,omitempty"`                              // last success timestamp
@@ -26,6 +27,41 @@ type QpWebhookPayload struct {
 	Extra interface{} `db:"extra" json:"extra,omitempty"` // extra info to append on payload
 }
 
+// Payload of queued messages final state
+type QpWebhookQueuePayload struct {
+	Event string       `json:"event"`
+	Job   *QpQueueItem `json:"job"`
+	Extra interface{}  `json:"extra,omitempty"` // extra info to append on payload
+}
+
+// Payload of message status changes, from receipts
+type QpWebhookReceiptPayload struct {
+	Event string `json:"event"`
+	*whatsapp.WhatsappReceipt
+	Extra interface{} `json:"extra,omitempty"` // extra info to append on payload
+}
+
+// Payload of messages from history sync
+type QpWebhookHistoryPayload struct {
+	Event string `json:"event"`
+	*whatsapp.WhatsappMessage
+	Extra interface{} `json:"extra,omitempty"` // extra info to append on payload
+}
+
+// Payload of group participants and metadata changes
+type QpWebhookGroupPayload struct {
+	Event string `json:"event"`
+	*whatsapp.WhatsappGroupEvent
+	Extra interface{} `json:"extra,omitempty"` // extra info to append on payload
+}
+
+// Payload of incoming calls
+type QpWebhookCallPayload struct {
+	Event string `json:"event"`
+	*whatsapp.WhatsappCall
+	Extra interface{} `json:"extra,omitempty"` // extra info to append on payload
+}
+
 var ErrInvalidResponse error = errors.New("the requested url do not return 200 status code")
 
 func (source *QpWebhook) Post(wid string, message *whatsapp.WhatsappMessage) (err error) {
@@ -36,6 +72,75 @@ func (source *QpWebhook) Post(wid string, message *whatsapp.WhatsappMessage) (er
 		Extra:           source.Extra,
 	}
 
+	return source.post(wid, payload)
+}
+
+// Final state of a queued message
+func (source *QpWebhook) PostQueue(wid string, item *QpQueueItem) (err error) {
+	log.Infof("dispatching queue webhook from: %s, to: %s", wid, source.Url)
+
+	payload := &QpWebhookQueuePayload{
+		Event: "queue",
+		Job:   item,
+		Extra: source.Extra,
+	}
+
+	return source.post(wid, payload)
+}
+
+// Delivery state of a message
+func (source *QpWebhook) PostReceipt(wid string, receipt *whatsapp.WhatsappReceipt) (err error) {
+	log.Infof("dispatching receipt webhook from: %s, to: %s", wid, source.Url)
+
+	payload := &QpWebhookReceiptPayload{
+		Event:           "status",
+		WhatsappReceipt: receipt,
+		Extra:           source.Extra,
+	}
+
+	return source.post(wid, payload)
+}
+
+// Message sent or received before this connection
+func (source *QpWebhook) PostHistory(wid string, message *whatsapp.WhatsappMessage) (err error) {
+	log.Debugf("dispatching history webhook from: %s, to: %s", wid, source.Url)
+
+	payload := &QpWebhookHistoryPayload{
+		Event:           "history",
+		WhatsappMessage: message,
+		Extra:           source.Extra,
+	}
+
+	return source.post(wid, payload)
+}
+
+// Participants or metadata change of a group
+func (source *QpWebhook) PostGroup(wid string, event *whatsapp.WhatsappGroupEvent) (err error) {
+	log.Infof("dispatching group webhook from: %s, to: %s", wid, source.Url)
+
+	payload := &QpWebhookGroupPayload{
+		Event:              "group",
+		WhatsappGroupEvent: event,
+		Extra:              source.Extra,
+	}
+
+	return source.post(wid, payload)
+}
+
+// Incoming call state
+func (source *QpWebhook) PostCall(wid string, call *whatsapp.WhatsappCall) (err error) {
+	log.Infof("dispatching call webhook from: %s, to: %s", wid, source.Url)
+
+	payload := &QpWebhookCallPayload{
+		Event:        "call",
+		WhatsappCall: call,
+		Extra:        source.Extra,
+	}
+
+	return source.post(wid, payload)
+}
+
+func (source *QpWebhook) post(wid string, payload interface{}) (err error) {
 	payloadJson, err := json.Marshal(&payload)
 	if err != nil {
 		return
@@ -35,6 +35,69 @@ func (w *QPWebhookHandler) Handle(payload *whatsapp.WhatsappMessage) {
 	PostToWebHookFromServer(w.Server, payload)
 }
 
+// Delivery and read receipts, updating campaign recipients and following to webhooks
+func (w *QPWebhookHandler) HandleReceipt(payload *whatsapp.WhatsappReceipt) {
+	if w.Server == nil {
+		return
+	}
+
+	status := GetCampaignRecipientStatus(payload.Status)
+	if len(status) > 0 && w.Server.Campaigns != nil {
+		err := w.Server.Campaigns.Receipt(payload.Id, status)
+		if err != nil {
+			log.Warnf("error on update campaign recipient from receipt: %s", err)
+		}
+	}
+
+	if !w.HasWebhook() {
+		return
+	}
+
+	PostReceiptToWebHookFromServer(w.Server, payload)
+}
+
+// Messages from history sync, only for webhooks with history enabled
+func (w *QPWebhookHandler) HandleHistory(payload *whatsapp.WhatsappMessage) {
+	if !w.HasWebhook() {
+		return
+	}
+
+	if payload.Type == whatsapp.DiscardMessageType || payload.Type == whatsapp.UnknownMessageType {
+		return
+	}
+
+	PostHistoryToWebHookFromServer(w.Server, payload)
+}
+
+// Group participants and metadata changes
+func (w *QPWebhookHandler) HandleGroup(payload *whatsapp.WhatsappGroupEvent) {
+	if !w.HasWebhook() {
+		return
+	}
+
+	PostGroupToWebHookFromServer(w.Server, payload)
+}
+
+// Incoming calls, rejected by bot settings before following to webhooks
+func (w *QPWebhookHandler) HandleCall(payload *whatsapp.WhatsappCall) {
+	if w.Server == nil {
+		return
+	}
+
+	if payload.Type == whatsapp.CallEventOffer && w.Server.RejectCalls() {
+		err := w.Server.RejectCall(payload)
+		if err != nil {
+			log.Warnf("error on reject call: %s", err)
+		}
+	}
+
+	if !w.HasWebhook() {
+		return
+	}
+
+	PostCallToWebHookFromServer(w.Server, payload)
+}
+
 func (w *QPWebhookHandler) HasWebhook() bool {
 	if w.Server != nil {
 		return len(w.Server.Webhooks) > 0
@@ -17,6 +17,7 @@ func ToWhatsappAttachment(source *QPAttachmentV1) (attach *whatsapp.WhatsappAtta
 	attach.FileName = source.FileName
 	attach.Mimetype = source.MIME
 	attach.FileLength = uint64(len(content))
+	attach.Ptt = whatsapp.IsVoiceMimetype(attach.Mimetype)
 	return
 }
 
@@ -17,6 +17,31 @@ func NewWhatsmeowConnection(wid string, logger *log.Logger) (whatsapp.IWhatsappC
 	return whatsmeow.WhatsmeowService.CreateConnection(wid, logger)
 }
 
+// Generates a new unique message id, used before sending
+func NewWhatsmeowMessageId() string {
+	return whatsmeow.GenerateMessageId()
+}
+
+// Connection or timeout errors, that may succeed on a new attempt
+func IsTransientWhatsmeowError(err error) bool {
+	return whatsmeow.IsTransientError(err)
+}
+
+// Serializes the original message content, used to download attachments later
+func MarshalWhatsmeowContent(content interface{}) ([]byte, error) {
+	return whatsmeow.MarshalContent(content)
+}
+
+// Restores the original message content from its serialized form
+func UnmarshalWhatsmeowContent(data []byte) (interface{}, error) {
+	return whatsmeow.UnmarshalContent(data)
+}
+
+// Memory used by the original message content, zero if unknown
+func GetWhatsmeowContentSize(content interface{}) int {
+	return whatsmeow.ContentSize(content)
+}
+
 func ToQPMessageV2(source whatsapp.WhatsappMessage, wid string) (message QPMessageV2) {
 	message.ID = source.Id
 	message.Timestamp = uint64(source.Timestamp.Unix())
@@ -12,9 +12,10 @@ import (
 
 // Serviço que controla os servidores / bots individuais do whatsapp
 type QPWhatsappHandlers struct {
-	messages     map[string]whatsapp.WhatsappMessage
+	messages     *QpMessageCache
 	sync         *sync.Mutex // Objeto de sinaleiro para evitar chamadas simultâneas a este objeto
 	syncRegister *sync.Mutex
+	syncHistory  *sync.Mutex // one history batch following to handlers at a time
 	log          *log.Entry
 
 	// Appended events handler
@@ -25,13 +26,16 @@ type QPWhatsappHandlers struct {
 	//filters
 	HandleGroups    bool
 	HandleBroadcast bool
+
+	// Persistent history, nil keeps messages only in memory
+	Store *QpMessageStore
 }
 
 //region CONTRUCTORS
 
 // Create a new QuePasa WhatsApp Event Handler
-func NewQPWhatsappHandlers(groups bool, broadcast bool, logger *log.Entry) (handler *QPWhatsappHandlers) {
-	handlerMessages := make(map[string]whatsapp.WhatsappMessage)
+func NewQPWhatsappHandlers(wid string, groups bool, broadcast bool, policy QpCachePolicy, logger *log.Entry) (handler *QPWhatsappHandlers) {
+	handlerMessages := NewQpMessageCache(wid, policy)
 	handler = &QPWhatsappHandlers{
 		HandleGroups:    groups,
 		HandleBroadcast: broadcast,
@@ -39,6 +43,7 @@ func NewQPWhatsappHandlers(groups bool, broadcast bool, logger *log.Entry) (hand
 		messages:     handlerMessages,
 		sync:         &sync.Mutex{},
 		syncRegister: &sync.Mutex{},
+		syncHistory:  &sync.Mutex{},
 		log:          logger,
 	}
 
@@ -64,10 +69,101 @@ func (handler *QPWhatsappHandlers) Message(msg *whatsapp.WhatsappMessage) {
 		return
 	}
 
+	if msg.Historical {
+		handler.History([]*whatsapp.WhatsappMessage{msg})
+		return
+	}
+
 	handler.log.Trace("msg recebida/(enviada por outro meio) em models: %s", msg.Id)
 	handler.appendMsgToCache(msg)
 }
 
+/*
+<summary>
+	Messages from history sync, saved directly on store and never cached, avoiding to evict live messages
+	Without store they are only followed to history handlers, one batch at a time
+</summary>
+*/
+func (handler *QPWhatsappHandlers) History(messages []*whatsapp.WhatsappMessage) {
+	var accepted []*whatsapp.WhatsappMessage
+	for _, msg := range messages {
+
+		// skipping groups if choosed
+		if !handler.HandleGroups && msg.FromGroup() {
+			continue
+		}
+
+		// skipping broadcast if choosed
+		if !handler.HandleBroadcast && msg.FromBroadcast() {
+			continue
+		}
+
+		if handler.Store != nil {
+			handler.Store.Save(msg)
+		}
+		accepted = append(accepted, msg)
+	}
+
+	if len(accepted) == 0 {
+		return
+	}
+
+	handler.log.Infof("history sync messages: %v, stored: %v", len(accepted), handler.Store != nil)
+	go handler.TriggerHistory(accepted)
+}
+
+// Updates the status of a cached message, following to receipt handlers
+func (handler *QPWhatsappHandlers) Receipt(receipt *whatsapp.WhatsappReceipt) {
+	handler.sync.Lock() // Sinal vermelho para atividades simultâneas
+
+	var updated *whatsapp.WhatsappMessage
+	normalizedId := strings.ToUpper(receipt.Id)
+	msg, cached := handler.messages.Get(normalizedId)
+	if cached {
+		if len(receipt.TrackId) == 0 {
+			receipt.TrackId = msg.TrackId
+		}
+
+		// group receipts comes from each participant, keeping the newest
+		if receipt.Status.IsAfter(msg.Status) {
+			msg.Status = receipt.Status
+			handler.messages.Set(msg)
+			updated = &msg
+		}
+	}
+
+	handler.sync.Unlock() // Sinal verde !
+
+	if handler.Store != nil {
+		if !cached {
+			handler.Store.Receipt(receipt)
+		} else if updated != nil {
+			handler.Store.SaveStatus(updated)
+		}
+	}
+
+	handler.log.Debugf("receipt: %s, status: %s", receipt.Id, receipt.Status)
+	handler.TriggerReceipt(receipt)
+}
+
+// Group participants and metadata changes, following to group handlers
+func (handler *QPWhatsappHandlers) GroupEvent(event *whatsapp.WhatsappGroupEvent) {
+
+	// skipping groups if choosed
+	if !handler.HandleGroups {
+		return
+	}
+
+	handler.log.Debugf("group event: %s, type: %s", event.ChatId, event.Type)
+	handler.TriggerGroup(event)
+}
+
+// Incoming calls, following to call handlers
+func (handler *QPWhatsappHandlers) Call(call *whatsapp.WhatsappCall) {
+	handler.log.Debugf("call: %s, from: %s, type: %s", call.Id, call.ChatId, call.Type)
+	handler.TriggerCall(call)
+}
+
 //#endregion
 //region MESSAGE CONTROL REGION HANDLE A LOCK
 
@@ -77,14 +173,16 @@ func (handler *QPWhatsappHandlers) appendMsgToCache(msg *whatsapp.WhatsappMessag
 	handler.sync.Lock() // Sinal vermelho para atividades simultâneas
 	// Apartir deste ponto só se executa um por vez
 
-	normalizedId := msg.Id
-	normalizedId = strings.ToUpper(normalizedId) // ensure that is an uppercase string before save
-
 	// saving on local normalized cache, do not afect remote msgs
-	handler.messages[normalizedId] = *msg
+	// ids are uppercase normalized, older entries are evicted by policy
+	handler.messages.Set(*msg)
 
 	handler.sync.Unlock() // Sinal verde !
 
+	if handler.Store != nil {
+		handler.Store.Save(msg)
+	}
+
 	// Executando WebHook de forma assincrona
 	handler.Trigger(msg)
 }
@@ -93,13 +191,63 @@ func (handler *QPWhatsappHandlers) GetMessages(timestamp time.Time) (messages []
 	handler.sync.Lock() // Sinal vermelho para atividades simultâneas
 	// Apartir deste ponto só se executa um por vez
 
-	for _, item := range handler.messages {
-		if item.Timestamp.After(timestamp) {
+	messages = handler.messages.GetAll(timestamp)
+
+	handler.sync.Unlock() // Sinal verde !
+
+	// older messages, from before the last restart
+	if handler.Store != nil {
+		cached := map[string]bool{}
+		for _, item := range messages {
+			cached[strings.ToUpper(item.Id)] = true
+		}
+
+		for _, item := range handler.Store.GetAll(timestamp, MessagesDefaultQueryLimit) {
+			if !cached[strings.ToUpper(item.Id)] {
+				messages = append(messages, item)
+			}
+		}
+	}
+	return
+}
+
+/*
+<summary>
+	Messages matching a receive filter, in memory ones are filtered locally
+	Older messages are filtered and limited by the store itself, not ordered
+</summary>
+*/
+func (handler *QPWhatsappHandlers) FindMessages(filter *QpReceiveFilter) (messages []whatsapp.WhatsappMessage, err error) {
+	handler.sync.Lock() // Sinal vermelho para atividades simultâneas
+	// Apartir deste ponto só se executa um por vez
+
+	for _, item := range handler.messages.GetAll(filter.After) {
+		if filter.Match(&item) {
 			messages = append(messages, item)
 		}
 	}
 
 	handler.sync.Unlock() // Sinal verde !
+
+	if handler.Store == nil {
+		return
+	}
+
+	stored, err := handler.Store.Find(filter)
+	if err != nil {
+		return
+	}
+
+	cached := map[string]bool{}
+	for _, item := range messages {
+		cached[strings.ToUpper(item.Id)] = true
+	}
+
+	for _, item := range stored {
+		if !cached[strings.ToUpper(item.Id)] {
+			messages = append(messages, item)
+		}
+	}
 	return
 }
 
@@ -112,15 +260,39 @@ func (handler *QPWhatsappHandlers) GetMessage(id string) (msg whatsapp.WhatsappM
 	normalizedId = strings.ToUpper(normalizedId) // ensure that is an uppercase string before save
 
 	// getting from local normalized cache, do not afect remote msgs
-	msg, ok := handler.messages[normalizedId]
+	msg, ok := handler.messages.Get(normalizedId)
+
+	handler.sync.Unlock() // Sinal verde !
+
+	if !ok && handler.Store != nil {
+		stored, serr := handler.Store.Get(normalizedId)
+		if serr != nil {
+			handler.log.Warnf("error on getting stored message: %s, %s", normalizedId, serr)
+		} else if stored != nil {
+			return *stored, nil
+		}
+	}
+
 	if !ok {
 		err = fmt.Errorf("message not present on handlers (cache) id: %s", normalizedId)
 	}
-
-	handler.sync.Unlock() // Sinal verde !
 	return msg, err
 }
 
+// Current limits of in memory messages
+func (handler *QPWhatsappHandlers) GetCachePolicy() QpCachePolicy {
+	handler.sync.Lock()
+	defer handler.sync.Unlock()
+	return handler.messages.Policy
+}
+
+// Replaces the limits of in memory messages, evicting the exceeding ones
+func (handler *QPWhatsappHandlers) SetCachePolicy(policy QpCachePolicy) {
+	handler.sync.Lock()
+	defer handler.sync.Unlock()
+	handler.messages.SetPolicy(policy)
+}
+
 //endregion
 //region EVENT HANDLER TO INTERNAL USE, GENERALY TO WEBHOOK
 
@@ -130,6 +302,60 @@ func (handler *QPWhatsappHandlers) Trigger(payload *whatsapp.WhatsappMessage) {
 	}
 }
 
+/*
+<summary>
+	Follows historical messages to registered handlers that accepts them
+	Sequentially and one batch at a time, history syncs may bring thousands of messages
+</summary>
+*/
+func (handler *QPWhatsappHandlers) TriggerHistory(messages []*whatsapp.WhatsappMessage) {
+	handler.syncHistory.Lock()
+	defer handler.syncHistory.Unlock()
+
+	for _, payload := range messages {
+		for _, handler := range handler.aeh {
+			if historyHandler, ok := handler.(interface {
+				HandleHistory(*whatsapp.WhatsappMessage)
+			}); ok {
+				historyHandler.HandleHistory(payload)
+			}
+		}
+	}
+}
+
+// Follows group events to registered handlers that accepts them
+func (handler *QPWhatsappHandlers) TriggerGroup(event *whatsapp.WhatsappGroupEvent) {
+	for _, handler := range handler.aeh {
+		if groupHandler, ok := handler.(interface {
+			HandleGroup(*whatsapp.WhatsappGroupEvent)
+		}); ok {
+			go groupHandler.HandleGroup(event)
+		}
+	}
+}
+
+// Follows calls to registered handlers that accepts them
+func (handler *QPWhatsappHandlers) TriggerCall(call *whatsapp.WhatsappCall) {
+	for _, handler := range handler.aeh {
+		if callHandler, ok := handler.(interface {
+			HandleCall(*whatsapp.WhatsappCall)
+		}); ok {
+			go callHandler.HandleCall(call)
+		}
+	}
+}
+
+// Follows receipts to registered handlers that accepts them
+func (handler *QPWhatsappHandlers) TriggerReceipt(receipt *whatsapp.WhatsappReceipt) {
+	for _, handler := range handler.aeh {
+		if receiptHandler, ok := handler.(interface {
+			HandleReceipt(*whatsapp.WhatsappReceipt)
+		}); ok {
+			go receiptHandler.HandleReceipt(receipt)
+		}
+	}
+}
+
 // Register an event handler that triggers on a new message received on cache
 func (handler *QPWhatsappHandlers) Register(evt interface {
 	Handle(*whatsapp.WhatsappMessage)
@@ -193,5 +419,21 @@ func (handler *QPWhatsappHandlers) IsRegistered(evt interface{}) bool {
 //endregion
 
 func (handler *QPWhatsappHandlers) GetTotal() int {
-	return len(handler.messages)
+	handler.sync.Lock()
+	defer handler.sync.Unlock()
+	return handler.messages.Len()
+}
+
+// Estimated memory of cached messages
+func (handler *QPWhatsappHandlers) GetCacheBytes() uint64 {
+	handler.sync.Lock()
+	defer handler.sync.Unlock()
+	return handler.messages.Bytes()
+}
+
+// Removes all cached messages, stored ones remains on database
+func (handler *QPWhatsappHandlers) ClearCache() {
+	handler.sync.Lock()
+	defer handler.sync.Unlock()
+	handler.messages.Clear()
 }
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +21,8 @@ type QPWhatsappServer struct {
 	Battery        WhatsAppBateryStatus         `json:"battery,omitempty"`
 	Timestamp      time.Time                    `json:"starttime,omitempty"`
 	Handler        *QPWhatsappHandlers          `json:"-"`
+	Queue          *QpServerQueue               `json:"-"` // durable outbound messages
+	Campaigns      *QpServerCampaigns           `json:"-"` // bulk sending with pacing
 
 	stopRequested bool        `json:"-"`
 	logger        *log.Logger `json:"-"`
@@ -29,7 +32,7 @@ type QPWhatsappServer struct {
 //region CONSTRUCTORS
 
 // Instanciando um novo servidor para controle de whatsapp
-func NewQPWhatsappServer(bot *QPBot, dbWHooks *QpDataWebhookInterface) (server *QPWhatsappServer, err error) {
+func NewQPWhatsappServer(bot *QPBot, dbWHooks *QpDataWebhookInterface, dbQueue QpDataQueueInterface, dbCampaign QpDataCampaignInterface, dbMessage QpDataMessageInterface) (server *QPWhatsappServer, err error) {
 	wid := bot.ID
 	var serverLogLevel log.Level
 	if bot.Devel {
@@ -42,7 +45,12 @@ func NewQPWhatsappServer(bot *QPBot, dbWHooks *QpDataWebhookInterface) (server *
 	serverLogger.SetLevel(serverLogLevel)
 	serverLogEntry := serverLogger.WithField("wid", wid)
 
-	handler := NewQPWhatsappHandlers(bot.HandleGroups, bot.HandleBroadcast, serverLogEntry)
+	handler := NewQPWhatsappHandlers(wid, bot.HandleGroups, bot.HandleBroadcast, bot.GetCachePolicy(), serverLogEntry)
+
+	// keeping history on database, survives restarts
+	if retention := ENV.MessagesRetention(); retention > 0 {
+		handler.Store = NewQpMessageStore(wid, dbMessage, retention, serverLogEntry)
+	}
 	server = &QPWhatsappServer{
 		Bot:            bot,
 		syncConnection: &sync.Mutex{},
@@ -57,6 +65,14 @@ func NewQPWhatsappServer(bot *QPBot, dbWHooks *QpDataWebhookInterface) (server *
 	}
 
 	server.WebhookFill(wid, *dbWHooks)
+
+	// delivers pending messages, even enqueued before a restart
+	server.Queue = NewQpServerQueue(server, dbQueue)
+	server.Queue.Start()
+
+	// resumes running campaigns, even started before a restart
+	server.Campaigns = NewQpServerCampaigns(server, dbCampaign)
+	server.Campaigns.Start()
 	return
 }
 
@@ -114,6 +130,11 @@ func (server *QPWhatsappServer) GetMessages(timestamp time.Time) (messages []wha
 	return
 }
 
+// Messages matching a receive filter, from memory and store, before pagination
+func (server *QPWhatsappServer) FindMessages(filter *QpReceiveFilter) ([]whatsapp.WhatsappMessage, error) {
+	return server.Handler.FindMessages(filter)
+}
+
 // Roda de forma assíncrona, não interessa o resultado ao chamador
 // Inicia o processo de tentativas de conexão de um servidor individual
 func (server *QPWhatsappServer) Initialize() {
@@ -405,6 +426,66 @@ func (server *QPWhatsappServer) HandleBroadcast() bool {
 	return server.Bot.HandleBroadcast
 }
 
+func (server *QPWhatsappServer) ToggleAutoRead() (err error) {
+	err = server.Bot.UpdateAutoRead(!server.Bot.AutoRead)
+	if err != nil {
+		return
+	}
+
+	server.Log.Infof("toggling auto read of received messages: %v", server.Bot.AutoRead)
+	return
+}
+
+func (server *QPWhatsappServer) AutoRead() bool {
+	return server.Bot.AutoRead
+}
+
+func (server *QPWhatsappServer) ToggleRejectCalls() (err error) {
+	err = server.Bot.UpdateRejectCalls(!server.Bot.RejectCalls)
+	if err != nil {
+		return
+	}
+
+	server.Log.Infof("toggling auto reject of incoming calls: %v", server.Bot.RejectCalls)
+	return
+}
+
+func (server *QPWhatsappServer) RejectCalls() bool {
+	return server.Bot.RejectCalls
+}
+
+// Text sent to callers after an automatic reject, empty for none
+func (server *QPWhatsappServer) UpdateCallReply(value string) (err error) {
+	err = server.Bot.UpdateCallReply(value)
+	if err != nil {
+		return
+	}
+
+	server.Log.Infof("updating reply for rejected calls, length: %v", len(value))
+	return
+}
+
+func (server *QPWhatsappServer) CallReply() string {
+	return server.Bot.CallReply
+}
+
+// Cache limits of this server, zero values follows the environment
+func (server *QPWhatsappServer) UpdateCachePolicy(entries uint, age uint, bytes uint64) (err error) {
+	err = server.Bot.UpdateCachePolicy(entries, age, bytes)
+	if err != nil {
+		return
+	}
+
+	policy := server.Bot.GetCachePolicy()
+	server.Handler.SetCachePolicy(policy)
+	server.Log.Infof("updating cache policy, entries: %v, age: %v, bytes: %v", policy.MaxEntries, policy.MaxAge, policy.MaxBytes)
+	return
+}
+
+func (server *QPWhatsappServer) CachePolicy() QpCachePolicy {
+	return server.Handler.GetCachePolicy()
+}
+
 func (server *QPWhatsappServer) ToggleDevel() (err error) {
 	err = server.Bot.UpdateDevel(!server.Bot.Devel)
 	if err != nil {
@@ -454,7 +535,8 @@ func (server *QPWhatsappServer) Delete() (err error) {
 func (server *QPWhatsappServer) SendMessage(msg *whatsapp.WhatsappMessage) (response whatsapp.IWhatsappSendResponse, err error) {
 	server.Log.Debugf("sending msg to: %v", msg.Chat.ID)
 
-	if msg.HasAttachment() {
+	// forwarded messages keeps the original caption
+	if msg.HasAttachment() && !msg.Forwarded {
 		if len(msg.Text) > 0 {
 
 			// Overriding filename with caption text if IMAGE or VIDEO
@@ -462,14 +544,27 @@ func (server *QPWhatsappServer) SendMessage(msg *whatsapp.WhatsappMessage) (resp
 				msg.Attachment.FileName = msg.Text
 			} else {
 
-				// Copying and send text before file
+				// Copying and send text before file, as a distinct message
 				textMsg := *msg
+				textMsg.Id = NewWhatsmeowMessageId()
 				textMsg.Type = whatsapp.TextMessageType
 				textMsg.Attachment = nil
 				_, err = server.connection.Send(&textMsg)
-				if err == nil {
-					server.Handler.Message(&textMsg)
+				if err != nil {
+					return
 				}
+
+				server.Handler.Message(&textMsg)
+
+				// text already delivered, retries should only resend the file
+				msg.Text = ""
+
+				// mentions already notified by the text, avoiding to ping twice
+				msg.Mentions = nil
+				msg.MentionAll = false
+
+				// already typed before the text, avoiding to wait twice
+				msg.TypingDelay = 0
 			}
 		}
 	}
@@ -477,8 +572,245 @@ func (server *QPWhatsappServer) SendMessage(msg *whatsapp.WhatsappMessage) (resp
 	// sending default msg
 	response, err = server.connection.Send(msg)
 	if err == nil {
+		msg.Status = whatsapp.MessageStatusSent
 		server.Handler.Message(msg)
+		server.Handler.Receipt(&whatsapp.WhatsappReceipt{
+			Id:        msg.Id,
+			TrackId:   msg.TrackId,
+			ChatId:    msg.Chat.ID,
+			Status:    msg.Status,
+			Timestamp: msg.Timestamp,
+		})
+	}
+	return
+}
+
+// Revoke (delete for everyone) a message sent by this server
+func (server *QPWhatsappServer) RevokeMessage(id string) (response whatsapp.IWhatsappSendResponse, err error) {
+	original, err := server.GetOwnMessage(id)
+	if err != nil {
+		return
+	}
+
+	server.Log.Infof("revoking msg: %s", original.Id)
+	msg := &whatsapp.WhatsappMessage{
+		Type:         whatsapp.RevokeMessageType,
+		Chat:         original.Chat,
+		InReply:      original.Id,
+		FromMe:       true,
+		FromInternal: true,
+	}
+	return server.SendMessage(msg)
+}
+
+// Replace the text of a message sent by this server
+func (server *QPWhatsappServer) EditMessage(id string, text string) (response whatsapp.IWhatsappSendResponse, err error) {
+	if len(text) == 0 {
+		err = fmt.Errorf("text not found, do not edit to empty messages")
+		return
+	}
+
+	original, err := server.GetOwnMessage(id)
+	if err != nil {
+		return
+	}
+
+	server.Log.Infof("editing msg: %s", original.Id)
+	msg := &whatsapp.WhatsappMessage{
+		Type:         whatsapp.EditMessageType,
+		Chat:         original.Chat,
+		Text:         text,
+		InReply:      original.Id,
+		FromMe:       true,
+		FromInternal: true,
+	}
+	return server.SendMessage(msg)
+}
+
+// Builds a copy of a cached message to another chat, marked as forwarded
+func (server *QPWhatsappServer) GetForwardMessage(id string, chatId string) (msg *whatsapp.WhatsappMessage, err error) {
+	original, err := server.Handler.GetMessage(id)
+	if err != nil {
+		return
 	}
+
+	switch original.Type {
+	case whatsapp.UnknownMessageType, whatsapp.ReactionMessageType, whatsapp.RevokeMessageType, whatsapp.EditMessageType, whatsapp.PollMessageType, whatsapp.PollVoteMessageType, whatsapp.DiscardMessageType:
+		err = fmt.Errorf("message of type %s cant be forwarded: %s", original.Type, original.Id)
+		return
+	}
+
+	msg = &whatsapp.WhatsappMessage{
+		Content:         original.Content,
+		Type:            original.Type,
+		Chat:            whatsapp.WhatsappChat{ID: chatId},
+		Text:            original.Text,
+		Attachment:      original.Attachment,
+		Location:        original.Location,
+		Contacts:        original.Contacts,
+		FromMe:          true,
+		FromInternal:    true,
+		Forwarded:       true,
+		ForwardingScore: original.ForwardingScore + 1,
+	}
+
+	// evicted or restored messages may lack the original content, required to forward media
+	if msg.Content == nil && msg.HasAttachment() && msg.Attachment.GetContent() == nil {
+		if server.Handler.Store != nil {
+			stored, serr := server.Handler.Store.Get(original.Id)
+			if serr == nil && stored != nil && stored.Content != nil {
+				msg.Content = stored.Content
+			}
+		}
+
+		// no original content at all, re-uploading the downloaded media
+		if msg.Content == nil {
+			data, derr := server.connection.DownloadData(&original)
+			if derr != nil {
+				err = fmt.Errorf("attachment content not available for forwarding: %s, %s", original.Id, derr)
+				return nil, err
+			}
+
+			attachment := *original.Attachment
+			attachment.SetContent(&data)
+			msg.Attachment = &attachment
+		}
+	}
+	return
+}
+
+// Get a cached message, ensuring that was sent by this server
+func (server *QPWhatsappServer) GetOwnMessage(id string) (msg whatsapp.WhatsappMessage, err error) {
+	msg, err = server.Handler.GetMessage(id)
+	if err != nil {
+		return
+	}
+
+	if !msg.FromMe {
+		err = fmt.Errorf("message not sent by this server: %s", msg.Id)
+	}
+	return
+}
+
+//#endregion
+//#region PRESENCE
+
+// Show typing or recording indicators on a chat
+func (server *QPWhatsappServer) SendChatPresence(chatId string, presence whatsapp.WhatsappChatPresence) error {
+	server.Log.Debugf("sending chat presence: %s, to: %s", presence, chatId)
+	return server.connection.SendChatPresence(chatId, presence)
+}
+
+// Set the account as online (available) or offline
+func (server *QPWhatsappServer) SendPresence(available bool) error {
+	server.Log.Debugf("sending presence, available: %v", available)
+	return server.connection.SendPresence(available)
+}
+
+//#endregion
+//#region READ RECEIPTS
+
+/*
+<summary>
+	Send read receipts for received messages, sender of each one is found on cache
+	Chat id is optional if all messages are on cache
+</summary>
+*/
+func (server *QPWhatsappServer) MarkRead(chatId string, ids ...string) (err error) {
+	if len(ids) == 0 {
+		err = fmt.Errorf("message ids missing")
+		return
+	}
+
+	// receipts are sent grouped by sender
+	senders := map[string][]string{}
+	for _, id := range ids {
+		sender := ""
+		cached, cerr := server.Handler.GetMessage(id)
+		if cerr == nil {
+			if cached.FromMe {
+				continue
+			}
+
+			if len(chatId) == 0 {
+				chatId = cached.Chat.ID
+			} else if chatId != cached.Chat.ID {
+				err = fmt.Errorf("message %s is not from chat: %s", id, chatId)
+				return
+			}
+
+			if cached.Participant != nil {
+				sender = cached.Participant.ID
+			}
+		} else if len(chatId) == 0 || strings.HasSuffix(chatId, "@g.us") {
+			err = cerr
+			return
+		}
+
+		senders[sender] = append(senders[sender], id)
+	}
+
+	for sender, messages := range senders {
+		server.Log.Debugf("marking as read: %v, on: %s", messages, chatId)
+		err = server.connection.MarkRead(chatId, sender, messages)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+//#endregion
+//#region REGISTERED
+
+// Checks if a chat id is a phone registered on whatsapp, groups are always valid
+func (server *QPWhatsappServer) IsOnWhatsApp(chatId string) (registered bool, err error) {
+	if !strings.HasSuffix(chatId, "@s.whatsapp.net") {
+		return true, nil
+	}
+
+	return server.connection.IsOnWhatsApp(chatId)
+}
+
+//#endregion
+//#region CALLS
+
+/*
+<summary>
+	Decline an incoming call, sending the configured reply text to the caller
+	Group calls are only declined, without reply
+</summary>
+*/
+func (server *QPWhatsappServer) RejectCall(call *whatsapp.WhatsappCall) (err error) {
+	server.Log.Infof("rejecting call: %s, from: %s", call.Id, call.ChatId)
+
+	from := call.ChatId
+	if call.Group && len(call.Creator) > 0 {
+		from = call.Creator
+	}
+
+	err = server.connection.RejectCall(from, call.Id)
+	if err != nil {
+		return
+	}
+
+	call.Rejected = true
+	reply := server.CallReply()
+	if len(reply) == 0 || call.Group {
+		return
+	}
+
+	msg := &whatsapp.WhatsappMessage{
+		Id:           NewWhatsmeowMessageId(),
+		Chat:         whatsapp.WhatsappChat{ID: call.ChatId},
+		Text:         reply,
+		Type:         whatsapp.TextMessageType,
+		FromMe:       true,
+		FromInternal: true,
+		Timestamp:    time.Now(),
+	}
+
+	_, err = server.SendMessage(msg)
 	return
 }
 
@@ -0,0 +1,352 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+// Interval to look for running campaigns when nothing changed
+const CampaignPollInterval = 10 * time.Second
+
+/*
+<summary>
+	Bulk sending of a single server
+	One recipient at a time, waiting the campaign pacing between each one
+	Running campaigns are processed in order of creation and resumed after restarts
+</summary>
+*/
+type QpServerCampaigns struct {
+	server *QPWhatsappServer
+	db     QpDataCampaignInterface
+
+	signal   chan bool
+	stop     chan bool
+	once     *sync.Once
+	disposed *sync.Once
+	next     time.Time // pacing, do not send before this time
+}
+
+func NewQpServerCampaigns(server *QPWhatsappServer, db QpDataCampaignInterface) *QpServerCampaigns {
+	return &QpServerCampaigns{
+		server:   server,
+		db:       db,
+		signal:   make(chan bool, 1),
+		stop:     make(chan bool),
+		once:     &sync.Once{},
+		disposed: &sync.Once{},
+	}
+}
+
+// Starts the background sending, only once
+func (source *QpServerCampaigns) Start() {
+	source.once.Do(func() {
+		go source.run()
+	})
+}
+
+// Stops the background sending, campaigns remains on database
+// Safe to call more than once, on restarts and removals
+func (source *QpServerCampaigns) Dispose() {
+	source.disposed.Do(func() {
+		close(source.stop)
+	})
+}
+
+// Wakes up the sending process
+func (source *QpServerCampaigns) Notify() {
+	select {
+	case source.signal <- true:
+	default:
+	}
+}
+
+/*
+<summary>
+	Stores and starts a new campaign, malformed and duplicated recipients are not sent
+	Duplicates are found by the formatted chat id, before validation
+	Recipients without all the variables used on text are marked as invalid
+</summary>
+*/
+func (source *QpServerCampaigns) Create(name string, pacing time.Duration, msg *whatsapp.WhatsappMessage, recipients []*QpCampaignRecipient) (campaign *QpCampaign, err error) {
+	if source.db == nil {
+		err = fmt.Errorf("campaign database not attached")
+		return
+	}
+
+	if len(recipients) == 0 {
+		err = fmt.Errorf("recipients missing")
+		return
+	}
+
+	campaign, err = NewQpCampaign(source.server.GetWid(), name, pacing, msg)
+	if err != nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	unique := make(map[string]bool)
+	var valid []*QpCampaignRecipient
+	for _, recipient := range recipients {
+		key := recipient.GetNormalizedChatId()
+		if unique[key] {
+			continue
+		}
+
+		unique[key] = true
+		recipient.Validate()
+		if recipient.Status == RecipientQueued {
+			if _, missing := ReplaceVariables(msg.Text, recipient.Variables); len(missing) > 0 {
+				recipient.Status = RecipientInvalid
+				recipient.Error = fmt.Sprintf("missing variables: %s", strings.Join(missing, ", "))
+			}
+		}
+
+		recipient.Campaign = campaign.ID
+		recipient.Position = uint(len(valid))
+		recipient.Updated = now
+		valid = append(valid, recipient)
+	}
+
+	err = source.db.Add(*campaign)
+	if err != nil {
+		return
+	}
+
+	err = source.db.AddRecipients(campaign.ID, valid)
+	if err != nil {
+		return
+	}
+
+	err = source.fill(campaign)
+	if err != nil {
+		return
+	}
+
+	source.server.Log.Infof("campaign created: %s, recipients: %v", campaign.ID, len(valid))
+	source.Notify()
+	return
+}
+
+// Single campaign with its summary, nil if not found
+func (source *QpServerCampaigns) Find(id string) (campaign *QpCampaign, err error) {
+	campaign, err = source.db.Find(source.server.GetWid(), id)
+	if err != nil || campaign == nil {
+		return
+	}
+
+	err = source.fill(campaign)
+	return
+}
+
+// All campaigns of this server with its summaries, optionally filtered by status
+func (source *QpServerCampaigns) FindAll(status QpCampaignStatus) (campaigns []*QpCampaign, err error) {
+	campaigns, err = source.db.FindAll(source.server.GetWid(), status)
+	if err != nil {
+		return
+	}
+
+	for _, campaign := range campaigns {
+		err = source.fill(campaign)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Recipients of a campaign of this server, optionally filtered by status
+func (source *QpServerCampaigns) FindRecipients(id string, status QpCampaignRecipientStatus) (recipients []*QpCampaignRecipient, err error) {
+	campaign, err := source.get(id)
+	if err != nil {
+		return
+	}
+
+	return source.db.FindRecipients(campaign.ID, status)
+}
+
+// Stops sending until resumed
+func (source *QpServerCampaigns) Pause(id string) (campaign *QpCampaign, err error) {
+	campaign, err = source.get(id)
+	if err != nil {
+		return
+	}
+
+	if campaign.Status != CampaignRunning {
+		err = fmt.Errorf("campaign not running: %s", campaign.Status)
+		return
+	}
+
+	return campaign, source.update(campaign, CampaignPaused)
+}
+
+// Continues sending, failed recipients are queued again
+func (source *QpServerCampaigns) Resume(id string) (campaign *QpCampaign, err error) {
+	campaign, err = source.get(id)
+	if err != nil {
+		return
+	}
+
+	if campaign.Status == CampaignCanceled {
+		err = fmt.Errorf("campaign canceled, cant be resumed")
+		return
+	}
+
+	err = source.db.Requeue(campaign.ID, RecipientFailed)
+	if err != nil {
+		return
+	}
+
+	err = source.update(campaign, CampaignRunning)
+	if err != nil {
+		return
+	}
+
+	source.Notify()
+	return
+}
+
+// Stops sending forever, queued recipients remains as is
+func (source *QpServerCampaigns) Cancel(id string) (campaign *QpCampaign, err error) {
+	campaign, err = source.get(id)
+	if err != nil {
+		return
+	}
+
+	if !campaign.IsActive() {
+		err = fmt.Errorf("campaign already finished as: %s", campaign.Status)
+		return
+	}
+
+	return campaign, source.update(campaign, CampaignCanceled)
+}
+
+// Updates the recipient of a sent message with its delivered or read receipt
+func (source *QpServerCampaigns) Receipt(messageId string, status QpCampaignRecipientStatus) error {
+	return source.db.UpdateRecipientByMessage(messageId, status)
+}
+
+func (source *QpServerCampaigns) get(id string) (campaign *QpCampaign, err error) {
+	campaign, err = source.Find(id)
+	if err == nil && campaign == nil {
+		err = fmt.Errorf("campaign not found: %s", id)
+	}
+	return
+}
+
+func (source *QpServerCampaigns) fill(campaign *QpCampaign) (err error) {
+	campaign.Summary, err = source.db.Summary(campaign.ID)
+	return
+}
+
+func (source *QpServerCampaigns) update(campaign *QpCampaign, status QpCampaignStatus) (err error) {
+	campaign.Status = status
+	campaign.Updated = time.Now().UTC()
+	err = source.db.Update(*campaign)
+	if err != nil {
+		return
+	}
+
+	source.server.Log.Infof("campaign: %s, %s", campaign.ID, campaign.Status)
+	return
+}
+
+func (source *QpServerCampaigns) run() {
+	for {
+		wait := CampaignPollInterval
+		if remaining := time.Until(source.next); remaining > 0 {
+			wait = remaining
+		}
+
+		select {
+		case <-source.stop:
+			return
+		case <-source.signal:
+		case <-time.After(wait):
+		}
+
+		// respecting the pacing, even when notified
+		if time.Now().Before(source.next) {
+			continue
+		}
+
+		// waiting for connection, do not mark recipients as failed
+		if source.server.GetStatus() != whatsapp.Ready {
+			continue
+		}
+
+		source.process()
+	}
+}
+
+// Sends to the next queued recipient of the oldest running campaign, finishing empty ones
+func (source *QpServerCampaigns) process() {
+	campaigns, err := source.db.FindAll(source.server.GetWid(), CampaignRunning)
+	if err != nil {
+		source.server.Log.Errorf("error on getting running campaigns: %s", err)
+		return
+	}
+
+	for _, campaign := range campaigns {
+		recipient, err := source.db.NextRecipient(campaign.ID)
+		if err != nil {
+			source.server.Log.Errorf("error on getting next recipient of campaign: %s, %s", campaign.ID, err)
+			return
+		}
+
+		if recipient == nil {
+			err = source.update(campaign, CampaignFinished)
+			if err != nil {
+				source.server.Log.Errorf("error on finishing campaign: %s, %s", campaign.ID, err)
+			}
+			continue
+		}
+
+		source.send(campaign, recipient)
+		source.next = time.Now().Add(campaign.GetPacing())
+		return
+	}
+}
+
+func (source *QpServerCampaigns) send(campaign *QpCampaign, recipient *QpCampaignRecipient) {
+	registered, err := source.server.IsOnWhatsApp(recipient.ChatId)
+	if err != nil {
+
+		// not sending without verification, resuming the campaign tries again
+		recipient.Status = RecipientFailed
+		recipient.Error = fmt.Sprintf("could not verify number: %s", err)
+	} else if !registered {
+		recipient.Status = RecipientInvalid
+		recipient.Error = "number not registered on whatsapp"
+	} else {
+
+		// known id before sending, used to match receipts
+		// a text sent apart from an attachment gets its own id, the recipient follows the attachment
+		recipient.MessageId = NewWhatsmeowMessageId()
+
+		msg, err := campaign.GetMessage(recipient)
+		if err == nil {
+			_, err = source.server.SendMessage(msg)
+			recipient.MessageId = msg.Id
+		}
+
+		if err != nil {
+			recipient.Status = RecipientFailed
+			recipient.Error = err.Error()
+		} else {
+			recipient.Status = RecipientSent
+			recipient.Error = ""
+		}
+	}
+
+	recipient.Updated = time.Now().UTC()
+	err = source.db.UpdateRecipient(*recipient)
+	if err != nil {
+		source.server.Log.Errorf("error on updating recipient: %s, of campaign: %s, %s", recipient.ChatId, campaign.ID, err)
+	}
+
+	source.server.Log.Debugf("campaign: %s, recipient: %s, %s", campaign.ID, recipient.ChatId, recipient.Status)
+}
@@ -16,15 +16,68 @@ func PostToWebHookFromServer(server *QPWhatsappServer, message *whatsapp.Whatsap
 	// Não cabe a nós a segurança do cliente
 	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 
+	accepted := false
 	for _, element := range server.Webhooks {
 		if !message.FromInternal || (element.ForwardInternal && (len(element.TrackId) == 0 || element.TrackId != message.TrackId)) {
-			element.Post(wid, message)
+			if element.Post(wid, message) == nil {
+				accepted = true
+			}
+		}
+	}
+
+	// marking as read only after some webhook accepted it
+	if accepted && server.AutoRead() && !message.FromMe && !message.FromBroadcast() {
+		err = server.MarkRead(message.Chat.ID, message.Id)
+		if err != nil {
+			server.Log.Warnf("auto read error: %s", err)
 		}
 	}
 
 	return
 }
 
+// Reports the final state of a queued message to all webhooks
+func PostQueueToWebHookFromServer(server *QPWhatsappServer, item *QpQueueItem) {
+	wid := server.GetWid()
+	for _, element := range server.Webhooks {
+		element.PostQueue(wid, item)
+	}
+}
+
+// Reports delivery and read status of a message to all webhooks
+func PostReceiptToWebHookFromServer(server *QPWhatsappServer, receipt *whatsapp.WhatsappReceipt) {
+	wid := server.GetWid()
+	for _, element := range server.Webhooks {
+		element.PostReceipt(wid, receipt)
+	}
+}
+
+// Follows messages from history sync to webhooks that asked for them
+func PostHistoryToWebHookFromServer(server *QPWhatsappServer, message *whatsapp.WhatsappMessage) {
+	wid := server.GetWid()
+	for _, element := range server.Webhooks {
+		if element.History {
+			element.PostHistory(wid, message)
+		}
+	}
+}
+
+// Reports group participants and metadata changes to all webhooks
+func PostGroupToWebHookFromServer(server *QPWhatsappServer, event *whatsapp.WhatsappGroupEvent) {
+	wid := server.GetWid()
+	for _, element := range server.Webhooks {
+		element.PostGroup(wid, event)
+	}
+}
+
+// Reports incoming calls to all webhooks
+func PostCallToWebHookFromServer(server *QPWhatsappServer, call *whatsapp.WhatsappCall) {
+	wid := server.GetWid()
+	for _, element := range server.Webhooks {
+		element.PostCall(wid, call)
+	}
+}
+
 //region FIND|SEARCH WHATSAPP SERVER
 var ErrServerNotFound error = errors.New("the requested whatsapp server was not found")
 
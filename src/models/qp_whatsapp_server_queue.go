@@ -0,0 +1,315 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+)
+
+const (
+	// Attempts before marking a queued message as failed
+	QueueMaxAttempts = 8
+
+	// Wait time after the first failure, doubled on each new attempt
+	QueueRetryInterval = 5 * time.Second
+
+	// Max wait time between attempts
+	QueueRetryMaxInterval = 10 * time.Minute
+
+	// Interval to look for pending messages when nothing was enqueued
+	QueuePollInterval = 10 * time.Second
+
+	// Items processed on each round
+	QueueBatchSize = 50
+
+	// Finished items are kept on database for this period
+	QueueRetention = 7 * 24 * time.Hour
+)
+
+/*
+<summary>
+	Durable outbound queue of a single server
+	Delivers in order when the connection is ready, retrying with backoff on errors
+</summary>
+*/
+type QpServerQueue struct {
+	server *QPWhatsappServer
+	db     QpDataQueueInterface
+
+	signal   chan bool
+	stop     chan bool
+	once     *sync.Once
+	disposed *sync.Once
+	purged   time.Time
+}
+
+func NewQpServerQueue(server *QPWhatsappServer, db QpDataQueueInterface) *QpServerQueue {
+	return &QpServerQueue{
+		server:   server,
+		db:       db,
+		signal:   make(chan bool, 1),
+		stop:     make(chan bool),
+		once:     &sync.Once{},
+		disposed: &sync.Once{},
+	}
+}
+
+// Starts the background delivery, only once
+func (queue *QpServerQueue) Start() {
+	queue.once.Do(func() {
+		go queue.run()
+	})
+}
+
+// Stops the background delivery, pending items remains on database
+// Safe to call more than once, on restarts and removals
+func (queue *QpServerQueue) Dispose() {
+	queue.disposed.Do(func() {
+		close(queue.stop)
+	})
+}
+
+// Stores a message for delivery, generating its id if empty
+func (queue *QpServerQueue) Enqueue(msg *whatsapp.WhatsappMessage) (item *QpQueueItem, err error) {
+	return queue.enqueue(msg, nil)
+}
+
+// Stores a message for delivery at a future time
+func (queue *QpServerQueue) Schedule(msg *whatsapp.WhatsappMessage, at time.Time) (item *QpQueueItem, err error) {
+	if !at.After(time.Now()) {
+		err = fmt.Errorf("schedule time already passed: %s", at)
+		return
+	}
+
+	return queue.enqueue(msg, &at)
+}
+
+func (queue *QpServerQueue) enqueue(msg *whatsapp.WhatsappMessage, at *time.Time) (item *QpQueueItem, err error) {
+	if queue.db == nil {
+		err = fmt.Errorf("queue database not attached")
+		return
+	}
+
+	// known id before delivery, useful to follow the message
+	if len(msg.Id) == 0 {
+		msg.Id = NewWhatsmeowMessageId()
+	}
+
+	item, err = NewQpQueueItem(queue.server.GetWid(), msg)
+	if err != nil {
+		return
+	}
+
+	if at != nil {
+		item.Status = QueueScheduled
+		item.Next = at.UTC()
+	}
+
+	err = queue.db.Add(*item)
+	if err != nil {
+		return
+	}
+
+	queue.server.Log.Infof("message %s, job: %s, id: %s, at: %s", item.Status, item.ID, item.MessageId, item.Next)
+	queue.Notify()
+	return
+}
+
+// Wakes up the delivery process
+func (queue *QpServerQueue) Notify() {
+	select {
+	case queue.signal <- true:
+	default:
+	}
+}
+
+func (queue *QpServerQueue) Find(id string) (*QpQueueItem, error) {
+	return queue.db.Find(queue.server.GetWid(), id)
+}
+
+func (queue *QpServerQueue) FindAll(status QpQueueStatus) ([]*QpQueueItem, error) {
+	return queue.db.FindAll(queue.server.GetWid(), status)
+}
+
+// Cancels a pending or scheduled item, before delivery
+func (queue *QpServerQueue) Cancel(id string) (item *QpQueueItem, err error) {
+	item, err = queue.Find(id)
+	if err != nil {
+		return
+	}
+
+	if item == nil {
+		err = fmt.Errorf("job not found: %s", id)
+		return
+	}
+
+	if !item.IsCancelable() {
+		err = fmt.Errorf("job already finished as: %s", item.Status)
+		return
+	}
+
+	// conditional, the delivery may claim the item meanwhile
+	now := time.Now().UTC()
+	canceled, err := queue.db.Cancel(item.Context, item.ID, now)
+	if err != nil {
+		return
+	}
+
+	if !canceled {
+		err = fmt.Errorf("job already claimed for delivery: %s", item.ID)
+		return
+	}
+
+	item.Status = QueueCanceled
+	item.Updated = now
+
+	queue.server.Log.Infof("queued message canceled, job: %s", item.ID)
+	return
+}
+
+func (queue *QpServerQueue) run() {
+
+	// interrupted deliveries on a previous run, sending again
+	err := queue.db.Release(queue.server.GetWid())
+	if err != nil {
+		queue.server.Log.Errorf("error on releasing queued messages: %s", err)
+	}
+
+	for {
+		select {
+		case <-queue.stop:
+			return
+		case <-queue.signal:
+		case <-time.After(QueuePollInterval):
+		}
+
+		err := queue.db.Promote(queue.server.GetWid(), time.Now().UTC())
+		if err != nil {
+			queue.server.Log.Errorf("error on promoting scheduled messages: %s", err)
+		}
+
+		// waiting for connection, do not waste attempts
+		if queue.server.GetStatus() != whatsapp.Ready {
+			continue
+		}
+
+		queue.process()
+		queue.purge()
+	}
+}
+
+// Delivers pending items in order, stops on the first one waiting for a new attempt
+func (queue *QpServerQueue) process() {
+	for {
+		items, err := queue.db.FindPending(queue.server.GetWid(), QueueBatchSize)
+		if err != nil {
+			queue.server.Log.Errorf("error on getting queued messages: %s", err)
+			return
+		}
+
+		for _, item := range items {
+			if item.Next.After(time.Now().UTC()) {
+				return
+			}
+
+			if !queue.deliver(item) {
+				return
+			}
+		}
+
+		if len(items) < QueueBatchSize {
+			return
+		}
+	}
+}
+
+/*
+<summary>
+	Sends a single item, returns false if it should be retried later
+	Only connection and timeout errors are retried, others fails immediately, releasing the items behind
+</summary>
+*/
+func (queue *QpServerQueue) deliver(item *QpQueueItem) bool {
+
+	// claiming before sending, canceled meanwhile are skipped
+	claimed, err := queue.db.Claim(item.Context, item.ID, time.Now().UTC())
+	if err != nil {
+		queue.server.Log.Errorf("error on claiming queued message: %s, %s", item.ID, err)
+		return false
+	}
+
+	if !claimed {
+		queue.server.Log.Infof("queued message: %s, not pending anymore, skipping", item.ID)
+		return true
+	}
+
+	transient := false
+	msg, err := item.GetMessage()
+	if err == nil {
+		text := msg.Text
+		_, err = queue.server.SendMessage(msg)
+		transient = IsTransientWhatsmeowError(err)
+
+		// partially sent, text part already delivered, keeping only what remains
+		if transient && msg.Text != text {
+			if payload, perr := MarshalQueuePayload(msg); perr == nil {
+				item.Payload = payload
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	item.Updated = now
+	item.Attempts++
+
+	if err == nil {
+		item.Status = QueueSent
+		item.Error = ""
+	} else {
+		item.Error = err.Error()
+		if !transient || item.Attempts >= QueueMaxAttempts {
+			item.Status = QueueFailed
+		} else {
+			item.Next = now.Add(GetQueueRetryInterval(item.Attempts))
+		}
+	}
+
+	err = queue.db.Update(*item)
+	if err != nil {
+		queue.server.Log.Errorf("error on updating queued message: %s, %s", item.ID, err)
+	}
+
+	if item.Status == QueuePending {
+		queue.server.Log.Warnf("queued message: %s, attempt: %v failed, retrying at: %s, error: %s", item.ID, item.Attempts, item.Next, item.Error)
+		return false
+	}
+
+	queue.server.Log.Infof("queued message: %s, finished as: %s", item.ID, item.Status)
+	go PostQueueToWebHookFromServer(queue.server, item)
+	return true
+}
+
+// Removes old finished items, once per hour
+func (queue *QpServerQueue) purge() {
+	if time.Since(queue.purged) < time.Hour {
+		return
+	}
+
+	queue.purged = time.Now()
+	err := queue.db.Purge(queue.server.GetWid(), time.Now().UTC().Add(-QueueRetention))
+	if err != nil {
+		queue.server.Log.Errorf("error on purging queued messages: %s", err)
+	}
+}
+
+// Exponential backoff, limited to the max interval
+func GetQueueRetryInterval(attempts uint) time.Duration {
+	interval := float64(QueueRetryInterval) * math.Pow(2, float64(attempts-1))
+	if interval > float64(QueueRetryMaxInterval) {
+		return QueueRetryMaxInterval
+	}
+	return time.Duration(interval)
+}
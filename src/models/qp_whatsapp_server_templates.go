@@ -0,0 +1,47 @@
+package models
+
+import "fmt"
+
+// Templates available to this server, owned by the same user
+func (server *QPWhatsappServer) GetTemplates() ([]*QpTemplate, error) {
+	return WhatsappService.DB.Template.FindAll(server.Bot.UserID, server.Bot.ID)
+}
+
+/*
+<summary>
+	Finds an available template by id or name
+	Templates restricted to this bot have priority over the ones for all bots
+</summary>
+*/
+func (server *QPWhatsappServer) GetTemplate(key string) (template *QpTemplate, err error) {
+	if len(key) == 0 {
+		err = fmt.Errorf("template missing")
+		return
+	}
+
+	db := WhatsappService.DB.Template
+	template, err = db.Find(server.Bot.UserID, key)
+	if err != nil {
+		return
+	}
+
+	if template == nil {
+		template, err = db.FindByName(server.Bot.UserID, server.Bot.ID, key)
+		if err != nil {
+			return
+		}
+	}
+
+	if template == nil {
+		template, err = db.FindByName(server.Bot.UserID, "", key)
+		if err != nil {
+			return
+		}
+	}
+
+	if template == nil || !template.IsAvailable(server.Bot.ID) {
+		err = fmt.Errorf("template not found: %s", key)
+		template = nil
+	}
+	return
+}
@@ -52,7 +52,7 @@ func (service *QPWhatsappService) AppendNewServer(bot *QPBot) (server *QPWhatsap
 	}
 
 	// Creating a new instance
-	server, err = NewQPWhatsappServer(bot, &service.DB.Webhook)
+	server, err = NewQPWhatsappServer(bot, &service.DB.Webhook, service.DB.Queue, service.DB.Campaign, service.DB.Message)
 	if err != nil {
 		log.Errorf("error on append new server: %s, :: %s", wid, err.Error())
 		return
@@ -104,6 +104,18 @@ func (service *QPWhatsappService) Delete(server *QPWhatsappServer) (err error) {
 		return
 	}
 
+	if server.Queue != nil {
+		server.Queue.Dispose()
+	}
+
+	if server.Campaigns != nil {
+		server.Campaigns.Dispose()
+	}
+
+	if server.Handler != nil {
+		server.Handler.ClearCache()
+	}
+
 	delete(service.Servers, wid)
 	return
 }
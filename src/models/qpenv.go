@@ -4,6 +4,9 @@ import (
 	"errors"
 	"os"
 	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
 )
 
 type Environment struct{}
@@ -43,6 +46,26 @@ func (_ *Environment) DEBUGJsonMessages() bool {
 	return false
 }
 
+// Period that messages are kept on database, as duration (720h) or days (30), zero keeps only in memory
+func (_ *Environment) MessagesRetention() time.Duration {
+	environment, err := getenvStr("MESSAGESRETENTION")
+	if err != nil {
+		return MessagesDefaultRetention
+	}
+
+	days, err := strconv.ParseUint(environment, 10, 32)
+	if err == nil {
+		return time.Duration(days) * 24 * time.Hour
+	}
+
+	duration, err := time.ParseDuration(environment)
+	if err != nil {
+		log.Warnf("invalid messages retention: %s, using default", environment)
+		return MessagesDefaultRetention
+	}
+	return duration
+}
+
 var ErrEnvVarEmpty = errors.New("getenv: environment variable empty")
 
 func GetEnvBool(key string, value bool) (bool, error) {
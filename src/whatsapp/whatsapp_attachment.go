@@ -14,9 +14,22 @@ type WhatsappAttachment struct {
 	// video | image | location (base64 image)
 	JpegThumbnail string `json:"thumbnail,omitempty"`
 
-	// audio
+	// video | image, original dimensions
+	Width  uint32 `json:"width,omitempty"`
+	Height uint32 `json:"height,omitempty"`
+
+	// pdf documents
+	PageCount uint32 `json:"pagecount,omitempty"`
+
+	// audio | video
 	Seconds uint32 `json:"seconds,omitempty"`
 
+	// audio, push to talk, sent or received as voice note
+	Ptt bool `json:"ptt,omitempty"`
+
+	// audio, voice note amplitudes, 64 values between 0 and 100
+	Waveform []byte `json:"waveform,omitempty"`
+
 	// location msgs
 	Latitude  float64 `json:"latitude,omitempty"`
 	Longitude float64 `json:"longitude,omitempty"`
@@ -0,0 +1,142 @@
+package whatsapp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Mimetype used by official clients on voice notes
+const VoiceMimetype = "audio/ogg; codecs=opus"
+
+// Amount of samples on a voice note waveform
+const WaveformSamples = 64
+
+// Sample rate used to decode audio when calculating duration and waveform
+const waveformSampleRate = 8000
+
+// Default behavior, ogg audios are sent as voice notes
+func IsVoiceMimetype(mimetype string) bool {
+	mimeOnly := strings.Split(mimetype, ";")
+	return mimeOnly[0] == "audio/ogg"
+}
+
+/*
+<summary>
+	Transcodes the attachment content to opus in ogg, filling duration and waveform
+	Requires ffmpeg available on path, attachment is untouched on any error
+</summary>
+*/
+func ConvertToVoice(attach *WhatsappAttachment) (err error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found, cant process audio: %s", err)
+	}
+
+	content := attach.GetContent()
+	if content == nil || len(*content) == 0 {
+		return fmt.Errorf("null or empty content")
+	}
+
+	directory, input, err := writeTempContent(*content)
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(directory)
+
+	output := filepath.Join(directory, "output.ogg")
+	err = runFFmpeg(ffmpeg, "-i", input, "-vn", "-ac", "1", "-ar", "48000", "-c:a", "libopus", "-b:a", "32k", "-application", "voip", "-f", "ogg", output)
+	if err != nil {
+		return
+	}
+
+	converted, err := ioutil.ReadFile(output)
+	if err != nil {
+		return
+	}
+
+	// decoding to raw samples, used for duration and waveform
+	pcm, err := outputFFmpeg(ffmpeg, "-i", output, "-f", "s16le", "-ac", "1", "-ar", fmt.Sprint(waveformSampleRate), "pipe:1")
+	if err != nil {
+		return
+	}
+
+	samples := len(pcm) / 2
+	attach.Seconds = uint32(math.Ceil(float64(samples) / waveformSampleRate))
+	attach.Waveform = GetWaveform(pcm, WaveformSamples)
+
+	attach.Mimetype = VoiceMimetype
+	attach.FileLength = uint64(len(converted))
+	attach.SetContent(&converted)
+	attach.Ptt = true
+
+	if len(attach.FileName) > 0 {
+		attach.FileName = strings.TrimSuffix(attach.FileName, filepath.Ext(attach.FileName)) + ".ogg"
+	}
+
+	return
+}
+
+// Generates a waveform from signed 16 bits little endian samples, values between 0 and 100
+func GetWaveform(pcm []byte, size int) (waveform []byte) {
+	waveform = make([]byte, size)
+
+	samples := len(pcm) / 2
+	if samples == 0 {
+		return
+	}
+
+	block := samples / size
+	if block == 0 {
+		block = 1
+	}
+
+	averages := make([]float64, size)
+	var peak float64
+	for i := 0; i < size; i++ {
+		start := i * block
+		if start >= samples {
+			break
+		}
+
+		end := start + block
+		if end > samples {
+			end = samples
+		}
+
+		var sum float64
+		for s := start; s < end; s++ {
+			value := int16(binary.LittleEndian.Uint16(pcm[s*2:]))
+			sum += math.Abs(float64(value))
+		}
+
+		averages[i] = sum / float64(end-start)
+		if averages[i] > peak {
+			peak = averages[i]
+		}
+	}
+
+	if peak == 0 {
+		return
+	}
+
+	for i, average := range averages {
+		waveform[i] = byte(math.Round(average / peak * 100))
+	}
+	return
+}
+
+func runFFmpeg(ffmpeg string, args ...string) error {
+	_, err := outputFFmpeg(ffmpeg, args...)
+	return err
+}
+
+func outputFFmpeg(ffmpeg string, args ...string) ([]byte, error) {
+	args = append([]string{"-y", "-hide_banner", "-loglevel", "error"}, args...)
+	return outputCommand(ffmpeg, args...)
+}
@@ -0,0 +1,40 @@
+package whatsapp
+
+import (
+	"time"
+)
+
+// Stage of a call
+type WhatsappCallEventType string
+
+const (
+	// Someone is calling this bot
+	CallEventOffer WhatsappCallEventType = "offer"
+
+	// Call accepted, on any device
+	CallEventAccept WhatsappCallEventType = "accept"
+
+	// Caller hung up or call ended
+	CallEventTerminate WhatsappCallEventType = "terminate"
+)
+
+// Incoming call event, voice or video, direct or group
+type WhatsappCall struct {
+	Id     string                `json:"id"`
+	Type   WhatsappCallEventType `json:"type"`
+	ChatId string                `json:"chatid"` // who is calling, or the group
+
+	// Who started the call, differs from chat on group calls
+	Creator string `json:"creator,omitempty"`
+
+	Video bool `json:"video,omitempty"`
+	Group bool `json:"group,omitempty"`
+
+	// Rejected automatically by bot settings
+	Rejected bool `json:"rejected,omitempty"`
+
+	// Reason of termination, when informed
+	Reason string `json:"reason,omitempty"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -22,6 +22,9 @@ type IWhatsappConnection interface {
 	// Get group invite link
 	GetInvite(groupId string) (string, error)
 
+	// Check if a phone number is registered on whatsapp
+	IsOnWhatsApp(phone string) (bool, error)
+
 	// Get info to download profile picture
 	GetProfilePicture(wid string, knowingId string) (*WhatsappProfilePicture, error)
 
@@ -37,6 +40,18 @@ type IWhatsappConnection interface {
 	// Default send message method
 	Send(*WhatsappMessage) (IWhatsappSendResponse, error)
 
+	// Show typing or recording indicators on a chat
+	SendChatPresence(chatId string, presence WhatsappChatPresence) error
+
+	// Set the account as online (available) or offline
+	SendPresence(available bool) error
+
+	// Send read receipts for messages of a chat, sender is required on groups
+	MarkRead(chatId string, senderId string, ids []string) error
+
+	// Decline an incoming call
+	RejectCall(from string, callId string) error
+
 	// Define the log level for this connection
 	UpdateLog(*log.Entry)
 
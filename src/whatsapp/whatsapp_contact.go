@@ -0,0 +1,59 @@
+package whatsapp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Contact card to send, a raw vcard or a structured name and phones list
+type WhatsappContact struct {
+	// Display name of the contact
+	Name string `json:"name"`
+
+	// E164 phones, used to generate a vcard if none passed
+	Phones []string `json:"phones,omitempty"`
+
+	// (Optional) Complete vcard content, overrides phones
+	VCard string `json:"vcard,omitempty"`
+}
+
+// Returns the informed vcard or generates one from structured data
+func (source *WhatsappContact) GetVCard() string {
+	if len(source.VCard) > 0 {
+		return source.VCard
+	}
+
+	var builder strings.Builder
+	builder.WriteString("BEGIN:VCARD\nVERSION:3.0\n")
+	builder.WriteString(fmt.Sprintf("N:;%s;;;\n", source.Name))
+	builder.WriteString(fmt.Sprintf("FN:%s\n", source.Name))
+
+	digits := regexp.MustCompile(`\D`)
+	for _, phone := range source.Phones {
+		waid := digits.ReplaceAllString(phone, "")
+		builder.WriteString(fmt.Sprintf("TEL;type=CELL;waid=%s:+%s\n", waid, waid))
+	}
+
+	builder.WriteString("END:VCARD")
+	return builder.String()
+}
+
+// Checks if there is enough information to send this contact
+func (source *WhatsappContact) Validate() error {
+	if len(strings.TrimSpace(source.Name)) == 0 {
+		return fmt.Errorf("contact name missing")
+	}
+
+	if len(source.VCard) == 0 && len(source.Phones) == 0 {
+		return fmt.Errorf("contact (%s) without phones or vcard", source.Name)
+	}
+
+	for _, phone := range source.Phones {
+		if !IsValidE164(phone) {
+			return fmt.Errorf("contact (%s) with an invalid phone: %s", source.Name, phone)
+		}
+	}
+
+	return nil
+}
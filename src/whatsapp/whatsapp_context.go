@@ -0,0 +1,20 @@
+package whatsapp
+
+// Message replied by another one, as sent with the reply
+type WhatsappQuoted struct {
+	Id          string              `json:"id"`
+	Type        WhatsappMessageType `json:"type"`
+	Text        string              `json:"text,omitempty"`
+	Participant string              `json:"participant,omitempty"` // who sent the quoted message
+}
+
+// Source of messages that comes from ads or shared links with preview (click to chat)
+type WhatsappAds struct {
+	Id           string `json:"id,omitempty"`
+	Type         string `json:"type,omitempty"` // ad, post, etc
+	Title        string `json:"title,omitempty"`
+	Body         string `json:"body,omitempty"`
+	SourceUrl    string `json:"sourceurl,omitempty"`
+	MediaUrl     string `json:"mediaurl,omitempty"`
+	ThumbnailUrl string `json:"thumbnailurl,omitempty"`
+}
@@ -102,7 +102,8 @@ func GetMessageType(Mimetype string) WhatsappMessageType {
 	case
 		"audio/ogg", "application/ogg", "audio/oga", "audio/ogx",
 		"audio/x-mpeg-3", "audio/mpeg3", "audio/mpeg",
-		"audio/mp4", "audio/wav", "audio/x-wav":
+		"audio/mp4", "audio/wav", "audio/x-wav", "audio/wave",
+		"audio/x-m4a", "audio/aac":
 		return AudioMessageType
 	case "video/mp4":
 		return VideoMessageType
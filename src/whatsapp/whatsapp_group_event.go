@@ -0,0 +1,69 @@
+package whatsapp
+
+import (
+	"time"
+)
+
+// Kind of change on a group
+type WhatsappGroupEventType string
+
+const (
+	// Participants joined or were added
+	GroupEventJoin WhatsappGroupEventType = "join"
+
+	// Participants left or were removed
+	GroupEventLeave WhatsappGroupEventType = "leave"
+
+	// Participants promoted to admins
+	GroupEventPromote WhatsappGroupEventType = "promote"
+
+	// Admins demoted to normal participants
+	GroupEventDemote WhatsappGroupEventType = "demote"
+
+	// Group name changed, value is the new name
+	GroupEventSubject WhatsappGroupEventType = "subject"
+
+	// Group description changed, value is the new description
+	GroupEventDescription WhatsappGroupEventType = "description"
+
+	// Group picture changed, value is the new picture id, empty when removed
+	GroupEventIcon WhatsappGroupEventType = "icon"
+
+	// Only admins can edit group info, value is true or false
+	GroupEventLocked WhatsappGroupEventType = "locked"
+
+	// Only admins can send messages, value is true or false
+	GroupEventAnnounce WhatsappGroupEventType = "announce"
+
+	// Disappearing messages changed, value is the timer in seconds, 0 when disabled
+	GroupEventEphemeral WhatsappGroupEventType = "ephemeral"
+
+	// Invite link changed, value is the new link
+	GroupEventInviteLink WhatsappGroupEventType = "invitelink"
+
+	// This bot was added to a group, value is the group name
+	GroupEventAdded WhatsappGroupEventType = "added"
+
+	// This bot left or was removed from a group
+	GroupEventRemoved WhatsappGroupEventType = "removed"
+)
+
+// Single change on a group, participants or metadata
+type WhatsappGroupEvent struct {
+	ChatId string                 `json:"chatid"`
+	Type   WhatsappGroupEventType `json:"type"`
+
+	// Who made the change, when known
+	Author string `json:"author,omitempty"`
+
+	// Affected participants, on join, leave, promote and demote
+	Participants []string `json:"participants,omitempty"`
+
+	// New value, on metadata changes
+	Value string `json:"value,omitempty"`
+
+	// "invite" when joined via invite link
+	Reason string `json:"reason,omitempty"`
+
+	Timestamp time.Time `json:"timestamp"`
+}
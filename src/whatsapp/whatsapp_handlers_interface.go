@@ -5,4 +5,19 @@ type IWhatsappHandlers interface {
 
 	// Recebimento/Envio de mensagem
 	Message(*WhatsappMessage)
+
+	// Past messages from history sync, a batch for each sync event
+	History([]*WhatsappMessage)
+
+	// Delivery and read receipts of messages
+	Receipt(*WhatsappReceipt)
+
+	// Participants and metadata changes of groups
+	GroupEvent(*WhatsappGroupEvent)
+
+	// Incoming calls
+	Call(*WhatsappCall)
+
+	// Get a single message from cache, if exists
+	GetMessage(id string) (WhatsappMessage, error)
 }
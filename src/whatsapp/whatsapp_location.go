@@ -0,0 +1,16 @@
+package whatsapp
+
+// Native location pin to send
+type WhatsappLocation struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+
+	// Title of the place
+	Name string `json:"name,omitempty"`
+
+	// Readable address of the place
+	Address string `json:"address,omitempty"`
+
+	// Public url with more information about the place
+	Url string `json:"url,omitempty"`
+}
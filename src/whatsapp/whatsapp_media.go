@@ -0,0 +1,292 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io/ioutil"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Max size in pixels of the larger side of generated thumbnails
+const ThumbnailMaxSize = 100
+
+// Quality of generated jpeg thumbnails
+const ThumbnailQuality = 60
+
+// Max time waiting for external media tools on each step
+const MediaProcessTimeout = 2 * time.Minute
+
+/*
+<summary>
+	Fills thumbnail, dimensions, duration and page count of outgoing media
+	Images are resized in process, videos requires ffmpeg and pdfs requires poppler utils
+</summary>
+*/
+func GenerateMediaInfo(attach *WhatsappAttachment) (err error) {
+	content := attach.GetContent()
+	if content == nil || len(*content) == 0 {
+		return fmt.Errorf("null or empty content")
+	}
+
+	mimeOnly := strings.TrimSpace(strings.Split(attach.Mimetype, ";")[0])
+	switch {
+	case strings.HasPrefix(mimeOnly, "image/"):
+		return fillImageInfo(attach, *content)
+	case strings.HasPrefix(mimeOnly, "video/"):
+		return fillVideoInfo(attach, *content)
+	case mimeOnly == "application/pdf":
+		return fillPdfInfo(attach, *content)
+	}
+
+	return
+}
+
+// Returns the decoded jpeg thumbnail, if any
+func (source *WhatsappAttachment) GetThumbnail() []byte {
+	if len(source.JpegThumbnail) == 0 {
+		return nil
+	}
+
+	thumbnail, err := base64.StdEncoding.DecodeString(source.JpegThumbnail)
+	if err != nil {
+		return nil
+	}
+	return thumbnail
+}
+
+func fillImageInfo(attach *WhatsappAttachment, content []byte) (err error) {
+	thumbnail, width, height, err := GetJpegThumbnail(content)
+	if err != nil {
+		return
+	}
+
+	attach.Width = width
+	attach.Height = height
+	attach.JpegThumbnail = base64.StdEncoding.EncodeToString(thumbnail)
+	return
+}
+
+func fillVideoInfo(attach *WhatsappAttachment, content []byte) (err error) {
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return fmt.Errorf("ffmpeg not found, cant process video: %s", err)
+	}
+
+	directory, input, err := writeTempContent(content)
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(directory)
+
+	// duration and dimensions are optional, thumbnail still generated
+	ffprobe, err := exec.LookPath("ffprobe")
+	if err == nil {
+		probe, err := outputCommand(ffprobe, "-v", "error", "-select_streams", "v:0", "-show_entries", "stream=width,height:format=duration", "-of", "json", input)
+		if err == nil {
+			fillVideoProbe(attach, probe)
+		}
+	}
+
+	frame, err := outputFFmpeg(ffmpeg, "-i", input, "-frames:v", "1", "-f", "image2", "-c:v", "mjpeg", "pipe:1")
+	if err != nil {
+		return
+	}
+
+	thumbnail, width, height, err := GetJpegThumbnail(frame)
+	if err != nil {
+		return
+	}
+
+	if attach.Width == 0 || attach.Height == 0 {
+		attach.Width = width
+		attach.Height = height
+	}
+
+	attach.JpegThumbnail = base64.StdEncoding.EncodeToString(thumbnail)
+	return
+}
+
+func fillVideoProbe(attach *WhatsappAttachment, probe []byte) {
+	result := struct {
+		Streams []struct {
+			Width  uint32 `json:"width"`
+			Height uint32 `json:"height"`
+		} `json:"streams"`
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+	}{}
+
+	err := json.Unmarshal(probe, &result)
+	if err != nil {
+		return
+	}
+
+	if len(result.Streams) > 0 {
+		attach.Width = result.Streams[0].Width
+		attach.Height = result.Streams[0].Height
+	}
+
+	duration, err := strconv.ParseFloat(result.Format.Duration, 64)
+	if err == nil {
+		attach.Seconds = uint32(math.Ceil(duration))
+	}
+}
+
+var regexPdfPages = regexp.MustCompile(`(?m)^Pages:\s+(\d+)`)
+
+func fillPdfInfo(attach *WhatsappAttachment, content []byte) (err error) {
+	pdftoppm, err := exec.LookPath("pdftoppm")
+	if err != nil {
+		return fmt.Errorf("pdftoppm not found, cant process pdf: %s", err)
+	}
+
+	directory, input, err := writeTempContent(content)
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(directory)
+
+	// page count is optional, thumbnail still generated
+	pdfinfo, err := exec.LookPath("pdfinfo")
+	if err == nil {
+		info, err := outputCommand(pdfinfo, input)
+		if err == nil {
+			matches := regexPdfPages.FindSubmatch(info)
+			if len(matches) > 1 {
+				pages, _ := strconv.ParseUint(string(matches[1]), 10, 32)
+				attach.PageCount = uint32(pages)
+			}
+		}
+	}
+
+	output := filepath.Join(directory, "page")
+	_, err = outputCommand(pdftoppm, "-f", "1", "-l", "1", "-singlefile", "-jpeg", "-scale-to", fmt.Sprint(ThumbnailMaxSize*4), input, output)
+	if err != nil {
+		return
+	}
+
+	page, err := ioutil.ReadFile(output + ".jpg")
+	if err != nil {
+		return
+	}
+
+	thumbnail, _, _, err := GetJpegThumbnail(page)
+	if err != nil {
+		return
+	}
+
+	attach.JpegThumbnail = base64.StdEncoding.EncodeToString(thumbnail)
+	return
+}
+
+// Decodes an image and returns a small jpeg thumbnail with the original dimensions
+func GetJpegThumbnail(content []byte) (thumbnail []byte, width uint32, height uint32, err error) {
+	source, _, err := image.Decode(bytes.NewReader(content))
+	if err != nil {
+		return
+	}
+
+	bounds := source.Bounds()
+	width = uint32(bounds.Dx())
+	height = uint32(bounds.Dy())
+
+	resized := ResizeImage(source, ThumbnailMaxSize)
+
+	buffer := new(bytes.Buffer)
+	err = jpeg.Encode(buffer, resized, &jpeg.Options{Quality: ThumbnailQuality})
+	if err != nil {
+		return
+	}
+
+	thumbnail = buffer.Bytes()
+	return
+}
+
+// Scales down an image keeping aspect ratio, averaging source pixels of each destination pixel
+func ResizeImage(source image.Image, max int) image.Image {
+	bounds := source.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= max && height <= max {
+		return source
+	}
+
+	ratio := math.Max(float64(width), float64(height)) / float64(max)
+	newWidth := int(math.Max(1, math.Round(float64(width)/ratio)))
+	newHeight := int(math.Max(1, math.Round(float64(height)/ratio)))
+
+	result := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
+	for y := 0; y < newHeight; y++ {
+		startY := bounds.Min.Y + y*height/newHeight
+		endY := bounds.Min.Y + (y+1)*height/newHeight
+		for x := 0; x < newWidth; x++ {
+			startX := bounds.Min.X + x*width/newWidth
+			endX := bounds.Min.X + (x+1)*width/newWidth
+
+			var r, g, b, a, count uint64
+			for sy := startY; sy < endY; sy++ {
+				for sx := startX; sx < endX; sx++ {
+					pr, pg, pb, pa := source.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					count++
+				}
+			}
+
+			if count == 0 {
+				continue
+			}
+
+			offset := result.PixOffset(x, y)
+			result.Pix[offset+0] = uint8(r / count >> 8)
+			result.Pix[offset+1] = uint8(g / count >> 8)
+			result.Pix[offset+2] = uint8(b / count >> 8)
+			result.Pix[offset+3] = uint8(a / count >> 8)
+		}
+	}
+	return result
+}
+
+// Writes content on a new temporary directory, caller must remove the directory
+func writeTempContent(content []byte) (directory string, input string, err error) {
+	directory, err = ioutil.TempDir("", "quepasa-media-")
+	if err != nil {
+		return
+	}
+
+	input = filepath.Join(directory, "input")
+	err = ioutil.WriteFile(input, content, 0600)
+	if err != nil {
+		os.RemoveAll(directory)
+	}
+	return
+}
+
+func outputCommand(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), MediaProcessTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %s, %s", filepath.Base(name), err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
@@ -0,0 +1,42 @@
+package whatsapp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Phone numbers prefixed by @ on text, as written by whatsapp clients
+var RegexMention = regexp.MustCompile(`@\+?(\d{8,15})\b`)
+
+/*
+<summary>
+	Users to mention on a sent message, as user ids
+	Explicit mentions first, then @number tokens found on text, without duplicates
+</summary>
+*/
+func (source *WhatsappMessage) GetMentions() (mentions []string) {
+	found := map[string]bool{}
+	appendMention := func(value string) {
+		value = strings.TrimSpace(value)
+		if len(value) == 0 {
+			return
+		}
+
+		wid := PhoneToWid(value)
+		if !strings.HasSuffix(wid, "@s.whatsapp.net") || found[wid] {
+			return
+		}
+
+		found[wid] = true
+		mentions = append(mentions, wid)
+	}
+
+	for _, mention := range source.Mentions {
+		appendMention(mention)
+	}
+
+	for _, match := range RegexMention.FindAllStringSubmatch(source.GetText(), -1) {
+		appendMention(match[1])
+	}
+	return
+}
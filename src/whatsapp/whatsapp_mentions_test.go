@@ -0,0 +1,46 @@
+package whatsapp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func assertMentions(t *testing.T, msg *WhatsappMessage, expected ...string) {
+	t.Helper()
+
+	mentions := msg.GetMentions()
+	if len(expected) == 0 && len(mentions) == 0 {
+		return
+	}
+
+	if !reflect.DeepEqual(mentions, expected) {
+		t.Errorf("expected: %v, got: %v", expected, mentions)
+	}
+}
+
+func TestGetMentionsExplicit(t *testing.T) {
+	assertMentions(t, &WhatsappMessage{Text: "hello"})
+	assertMentions(t, &WhatsappMessage{Mentions: []string{"+5521999990001"}}, "5521999990001@s.whatsapp.net")
+	assertMentions(t, &WhatsappMessage{Mentions: []string{"5521999990001@s.whatsapp.net"}}, "5521999990001@s.whatsapp.net")
+
+	// groups and blank entries are not valid mentions
+	assertMentions(t, &WhatsappMessage{Mentions: []string{"", "120363000000000000@g.us"}})
+}
+
+func TestGetMentionsFromText(t *testing.T) {
+	msg := &WhatsappMessage{Text: "hi @5521999990001 and @+5521999990002"}
+	assertMentions(t, msg, "5521999990001@s.whatsapp.net", "5521999990002@s.whatsapp.net")
+
+	msg = &WhatsappMessage{Text: "call @123 now"}
+	assertMentions(t, msg)
+}
+
+func TestGetMentionsDeduplicates(t *testing.T) {
+	msg := &WhatsappMessage{
+		Text:     "@5521999990001 @5521999990002",
+		Mentions: []string{"5521999990002", " +5521999990002 "},
+	}
+
+	// explicit mentions come first, text mentions are appended once
+	assertMentions(t, msg, "5521999990002@s.whatsapp.net", "5521999990001@s.whatsapp.net")
+}
@@ -29,6 +29,18 @@ type WhatsappMessage struct {
 
 	Attachment *WhatsappAttachment `json:"attachment,omitempty"`
 
+	// Native location pin, used on sending
+	Location *WhatsappLocation `json:"location,omitempty"`
+
+	// Contact cards, used on sending
+	Contacts []WhatsappContact `json:"contacts,omitempty"`
+
+	// Native poll, on creation or vote updates
+	Poll *WhatsappPoll `json:"poll,omitempty"`
+
+	// Style of text status (story)
+	Story *WhatsappStory `json:"story,omitempty"`
+
 	// Do i send that ?
 	// From any connected device and api
 	FromMe bool `json:"fromme"`
@@ -39,8 +51,44 @@ type WhatsappMessage struct {
 	// Quantas vezes essa msg foi encaminhada
 	ForwardingScore uint32 `json:"forwardingscore,omitempty"`
 
+	// Marked as forwarded, re-sending the original content when available
+	Forwarded bool `json:"forwarded,omitempty"`
+
+	// Received already marked as forwarded by the sender
+	IsForwarded bool `json:"isforwarded,omitempty"`
+
 	// Msg in reply of another ? Message ID
 	InReply string `json:"inreply,omitempty"`
+
+	// Content of the replied message, on received messages
+	Quoted *WhatsappQuoted `json:"quoted,omitempty"`
+
+	// Mentioned users ids (@), on received and sent messages
+	Mentions []string `json:"mentions,omitempty"`
+
+	// Mention every participant, when sending to groups
+	MentionAll bool `json:"mentionall,omitempty"`
+
+	// Ad or link preview that originated this message
+	Ads *WhatsappAds `json:"ads,omitempty"`
+
+	// Max milliseconds showing typing indicator before sending
+	TypingDelay uint `json:"typingdelay,omitempty"`
+
+	// Latest delivery state, from receipts
+	Status WhatsappMessageStatus `json:"status,omitempty"`
+
+	// Received from history sync, sent or received before this connection
+	Historical bool `json:"historical,omitempty"`
+
+	// Sent on a chat with disappearing messages
+	Ephemeral bool `json:"ephemeral,omitempty"`
+
+	// Seconds until disappear, from chat settings
+	Expiration uint32 `json:"expiration,omitempty"`
+
+	// Media that can be opened only once
+	ViewOnce bool `json:"viewonce,omitempty"`
 }
 
 //region ORDER BY TIMESTAMP
@@ -88,7 +136,7 @@ func (source *WhatsappMessage) FromGroup() bool {
 }
 
 func (source *WhatsappMessage) FromBroadcast() bool {
-	return source.Chat.ID == "status" || source.Chat.ID == "status@broadcast"
+	return source.Chat.ID == "status" || source.Chat.ID == StatusBroadcastChatId
 }
 
 func (source *WhatsappMessage) GetAttachment() *WhatsappAttachment {
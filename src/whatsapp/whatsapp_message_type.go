@@ -1,5 +1,11 @@
 package whatsapp
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
 type WhatsappMessageType uint
 
 const (
@@ -12,6 +18,21 @@ const (
 	LocationMessageType
 	ContactMessageType
 
+	// Reaction to another message, Text is the emoji and InReply the target message id
+	ReactionMessageType
+
+	// Message deleted for everyone, InReply is the revoked message id
+	RevokeMessageType
+
+	// Message text changed, Text is the new content and InReply the edited message id
+	EditMessageType
+
+	// Native poll creation, Text is the question
+	PollMessageType
+
+	// Vote on a poll, InReply is the poll message id and Text the selected options
+	PollVoteMessageType
+
 	// Messages that isn't important for this whatsapp service
 	DiscardMessageType
 )
@@ -32,7 +53,31 @@ func (Type WhatsappMessageType) String() string {
 		return "location"
 	case ContactMessageType:
 		return "contact"
+	case ReactionMessageType:
+		return "reaction"
+	case RevokeMessageType:
+		return "revoked"
+	case EditMessageType:
+		return "edited"
+	case PollMessageType:
+		return "poll"
+	case PollVoteMessageType:
+		return "pollvote"
 	}
 
 	return "unknown"
 }
+
+// Message type from its name or number, as on json
+func ParseMessageType(value string) (WhatsappMessageType, error) {
+	if number, err := strconv.ParseUint(value, 10, 32); err == nil && number <= uint64(DiscardMessageType) {
+		return WhatsappMessageType(number), nil
+	}
+
+	for Type := ImageMessageType; Type < DiscardMessageType; Type++ {
+		if strings.EqualFold(Type.String(), value) {
+			return Type, nil
+		}
+	}
+	return UnknownMessageType, fmt.Errorf("invalid message type: %s", value)
+}
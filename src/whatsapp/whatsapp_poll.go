@@ -0,0 +1,19 @@
+package whatsapp
+
+// Native poll, used on creation and on vote updates
+type WhatsappPoll struct {
+	// Question of the poll
+	Question string `json:"question"`
+
+	// Names of the available options
+	Options []string `json:"options,omitempty"`
+
+	// How many options a voter can select, 0 means any
+	Selectable uint32 `json:"selectable,omitempty"`
+
+	// Names of the options selected on a vote update, empty means vote removed
+	Votes []string `json:"votes,omitempty"`
+
+	// Encryption secret of the poll, required to decode votes
+	Secret []byte `json:"-"`
+}
@@ -0,0 +1,91 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Presence shown on a single chat, typing or recording indicators
+type WhatsappChatPresence string
+
+const (
+	// Typing... indicator
+	ChatPresenceComposing WhatsappChatPresence = "composing"
+
+	// Recording audio... indicator
+	ChatPresenceRecording WhatsappChatPresence = "recording"
+
+	// Stops any indicator
+	ChatPresencePaused WhatsappChatPresence = "paused"
+)
+
+var WhatsappChatPresences = []WhatsappChatPresence{
+	ChatPresenceComposing,
+	ChatPresenceRecording,
+	ChatPresencePaused,
+}
+
+func ParseChatPresence(value string) (presence WhatsappChatPresence, err error) {
+	for _, item := range WhatsappChatPresences {
+		if strings.EqualFold(string(item), value) {
+			return item, nil
+		}
+	}
+
+	err = fmt.Errorf("invalid chat presence: %s, accepted: composing, recording, paused", value)
+	return
+}
+
+// Time spent typing each character of text, on simulated typing
+const TypingDurationPerChar = 50 * time.Millisecond
+
+// Min time of simulated typing, short texts still shows the indicator
+const TypingDurationMin = 1 * time.Second
+
+// Max time of simulated typing, the sender stays blocked while typing
+const TypingDurationMax = 5 * time.Second
+
+// Ensures that the requested typing delay (milliseconds) does not exceeds the server max
+func ValidateTypingDelay(delay uint) error {
+	if time.Duration(delay)*time.Millisecond > TypingDurationMax {
+		return fmt.Errorf("typing delay too long: %vms, max: %vms", delay, TypingDurationMax.Milliseconds())
+	}
+	return nil
+}
+
+// Indicator shown before sending this message, recording for voice notes
+func (source *WhatsappMessage) GetChatPresence() WhatsappChatPresence {
+	if source.HasAttachment() && source.Attachment.Ptt {
+		return ChatPresenceRecording
+	}
+	return ChatPresenceComposing
+}
+
+/*
+<summary>
+	Time to show the typing indicator before sending, proportional to the text length
+	Limited by TypingDelay (milliseconds), messages without text uses the whole delay
+	Never exceeds TypingDurationMax, whatever was requested
+</summary>
+*/
+func (source *WhatsappMessage) GetTypingDuration() (duration time.Duration) {
+	limit := time.Duration(source.TypingDelay) * time.Millisecond
+	if limit > TypingDurationMax {
+		limit = TypingDurationMax
+	}
+
+	if len(source.Text) == 0 {
+		return limit
+	}
+
+	duration = time.Duration(len([]rune(source.Text))) * TypingDurationPerChar
+	if duration < TypingDurationMin {
+		duration = TypingDurationMin
+	}
+
+	if duration > limit {
+		duration = limit
+	}
+	return
+}
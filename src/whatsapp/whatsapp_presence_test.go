@@ -0,0 +1,68 @@
+package whatsapp
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func typingDuration(delay uint, text string) time.Duration {
+	msg := &WhatsappMessage{Text: text, TypingDelay: delay}
+	return msg.GetTypingDuration()
+}
+
+func TestGetTypingDuration(t *testing.T) {
+	if d := typingDuration(0, "hello"); d != 0 {
+		t.Errorf("without delay, got %v", d)
+	}
+
+	if d := typingDuration(3000, ""); d != 3*time.Second {
+		t.Errorf("messages without text should use the whole delay, got %v", d)
+	}
+
+	if d := typingDuration(3000, "hi"); d != TypingDurationMin {
+		t.Errorf("short texts should use the min duration, got %v", d)
+	}
+
+	if d := typingDuration(3000, strings.Repeat("a", 40)); d != 40*TypingDurationPerChar {
+		t.Errorf("expected proportional duration, got %v", d)
+	}
+
+	if d := typingDuration(3000, strings.Repeat("a", 100)); d != 3*time.Second {
+		t.Errorf("expected limited by delay, got %v", d)
+	}
+
+	if d := typingDuration(500, "hi"); d != 500*time.Millisecond {
+		t.Errorf("delay below min should win, got %v", d)
+	}
+
+	// counting characters, not bytes
+	if d := typingDuration(5000, strings.Repeat("ç", 40)); d != 40*TypingDurationPerChar {
+		t.Errorf("expected duration by runes, got %v", d)
+	}
+}
+
+func TestGetTypingDurationServerMax(t *testing.T) {
+	if d := typingDuration(60000, ""); d != TypingDurationMax {
+		t.Errorf("expected capped at %v, got %v", TypingDurationMax, d)
+	}
+
+	if d := typingDuration(60000, strings.Repeat("a", 1000)); d != TypingDurationMax {
+		t.Errorf("expected capped at %v, got %v", TypingDurationMax, d)
+	}
+}
+
+func TestValidateTypingDelay(t *testing.T) {
+	max := uint(TypingDurationMax.Milliseconds())
+	for _, delay := range []uint{0, 1000, max} {
+		if err := ValidateTypingDelay(delay); err != nil {
+			t.Errorf("%v: unexpected error: %s", delay, err)
+		}
+	}
+
+	for _, delay := range []uint{max + 1, 3600000} {
+		if err := ValidateTypingDelay(delay); err == nil {
+			t.Errorf("%v: expected error", delay)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package whatsapp
+
+import (
+	"time"
+)
+
+// Delivery state of a message, only moves forward
+type WhatsappMessageStatus string
+
+const (
+	// Accepted by whatsapp servers
+	MessageStatusSent WhatsappMessageStatus = "sent"
+
+	// Received on recipient device
+	MessageStatusDelivered WhatsappMessageStatus = "delivered"
+
+	// Recipient opened the chat and saw the message
+	MessageStatusRead WhatsappMessageStatus = "read"
+
+	// Recipient played the audio, video or view once media
+	MessageStatusPlayed WhatsappMessageStatus = "played"
+)
+
+// Known delivery states, in order
+var MessageStatuses = []WhatsappMessageStatus{MessageStatusSent, MessageStatusDelivered, MessageStatusRead, MessageStatusPlayed}
+
+// Order of delivery states, unknown values are lowest
+func (source WhatsappMessageStatus) Level() int {
+	switch source {
+	case MessageStatusSent:
+		return 1
+	case MessageStatusDelivered:
+		return 2
+	case MessageStatusRead:
+		return 3
+	case MessageStatusPlayed:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// Indicates that this status is newer than another one
+func (source WhatsappMessageStatus) IsAfter(status WhatsappMessageStatus) bool {
+	return source.Level() > status.Level()
+}
+
+// Status change of a single message, from delivery and read receipts
+type WhatsappReceipt struct {
+	Id      string `json:"id"`
+	TrackId string `json:"trackid,omitempty"` // from the sent message, if still on cache
+	ChatId  string `json:"chatid"`
+
+	// Who received or read, on groups
+	Participant string `json:"participant,omitempty"`
+
+	Status    WhatsappMessageStatus `json:"status"`
+	Timestamp time.Time             `json:"timestamp"`
+}
@@ -0,0 +1,85 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Chat id used to post and receive status (stories)
+const StatusBroadcastChatId = "status@broadcast"
+
+// Fonts available on text status, in the same order of whatsapp enumeration
+var WhatsappStoryFonts = []string{
+	"sans_serif",
+	"serif",
+	"norican_regular",
+	"bryndan_write",
+	"bebasneue_regular",
+	"oswald_heavy",
+}
+
+// Style of a text status (story)
+type WhatsappStory struct {
+	BackgroundColor string `json:"backgroundcolor,omitempty"` // #RRGGBB or #AARRGGBB
+	TextColor       string `json:"textcolor,omitempty"`       // #RRGGBB or #AARRGGBB
+	Font            string `json:"font,omitempty"`            // one of WhatsappStoryFonts
+}
+
+func (source *WhatsappStory) Validate() (err error) {
+	if len(source.BackgroundColor) > 0 {
+		_, err = ParseArgb(source.BackgroundColor)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(source.TextColor) > 0 {
+		_, err = ParseArgb(source.TextColor)
+		if err != nil {
+			return
+		}
+	}
+
+	if len(source.Font) > 0 && source.GetFontIndex() < 0 {
+		err = fmt.Errorf("invalid font: %s, accepted: %s", source.Font, strings.Join(WhatsappStoryFonts, ", "))
+	}
+	return
+}
+
+// Index of font on whatsapp enumeration, -1 if unknown
+func (source *WhatsappStory) GetFontIndex() int {
+	for index, font := range WhatsappStoryFonts {
+		if strings.EqualFold(font, source.Font) {
+			return index
+		}
+	}
+	return -1
+}
+
+// Color as #RRGGBB (opaque) or #AARRGGBB to argb integer
+func ParseArgb(color string) (argb uint32, err error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(color), "#")
+	if len(hex) == 6 {
+		hex = "FF" + hex
+	}
+
+	if len(hex) != 8 {
+		err = fmt.Errorf("invalid color: %s, use #RRGGBB or #AARRGGBB", color)
+		return
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		err = fmt.Errorf("invalid color: %s, use #RRGGBB or #AARRGGBB", color)
+		return
+	}
+
+	argb = uint32(value)
+	return
+}
+
+// Argb integer to #AARRGGBB
+func FormatArgb(argb uint32) string {
+	return fmt.Sprintf("#%08X", argb)
+}
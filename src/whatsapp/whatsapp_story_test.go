@@ -0,0 +1,60 @@
+package whatsapp
+
+import "testing"
+
+func TestParseArgb(t *testing.T) {
+	valid := map[string]uint32{
+		"#FF0000":   0xFFFF0000, // opaque when alpha is omitted
+		"#80FF0000": 0x80FF0000,
+		"00ff00":    0xFF00FF00,
+		" #0000FF ": 0xFF0000FF,
+		"#00000000": 0,
+	}
+
+	for color, expected := range valid {
+		argb, err := ParseArgb(color)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", color, err)
+		} else if argb != expected {
+			t.Errorf("%q: expected %X, got %X", color, expected, argb)
+		}
+	}
+
+	for _, color := range []string{"", "#FFF", "#GGGGGG", "#FF00000000"} {
+		if argb, err := ParseArgb(color); err == nil {
+			t.Errorf("%q: expected error, got %X", color, argb)
+		}
+	}
+}
+
+func TestFormatArgbRoundTrip(t *testing.T) {
+	if color := FormatArgb(0x80FF0000); color != "#80FF0000" {
+		t.Fatalf("unexpected format: %s", color)
+	}
+
+	argb, err := ParseArgb(FormatArgb(0x0A0B0C0D))
+	if err != nil || argb != 0x0A0B0C0D {
+		t.Errorf("round trip differs: %X, %v", argb, err)
+	}
+}
+
+func TestStoryValidate(t *testing.T) {
+	story := &WhatsappStory{BackgroundColor: "#112233", TextColor: "#FFFFFFFF", Font: "SERIF"}
+	if err := story.Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if index := story.GetFontIndex(); index != 1 {
+		t.Errorf("expected serif as font 1, got %v", index)
+	}
+
+	story.BackgroundColor = "red"
+	if err := story.Validate(); err == nil {
+		t.Error("accepted an invalid background")
+	}
+
+	story = &WhatsappStory{Font: "comic_sans"}
+	if err := story.Validate(); err == nil {
+		t.Error("accepted an unknown font")
+	}
+}
@@ -3,13 +3,17 @@ package whatsmeow
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 	"unicode"
 
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
 
 	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
 	whatsmeow "go.mau.fi/whatsmeow"
+	waBinary "go.mau.fi/whatsmeow/binary"
 	waProto "go.mau.fi/whatsmeow/binary/proto"
 	types "go.mau.fi/whatsmeow/types"
 	waLog "go.mau.fi/whatsmeow/util/log"
@@ -23,6 +27,10 @@ type WhatsmeowConnection struct {
 	logger      *log.Logger
 	log         *log.Entry
 	failedToken bool
+
+	// closed on dispose, interrupts pending waits
+	disposed  chan struct{}
+	disposing sync.Once
 }
 
 //region IMPLEMENT INTERFACE WHATSAPP CONNECTION
@@ -118,6 +126,9 @@ func (conn *WhatsmeowConnection) DownloadData(imsg whatsapp.IWhatsappMessage) (d
 			err = fmt.Errorf("parameter msg cannot be converted to an original message")
 			return
 		}
+
+		// media inside ephemeral, view once or document with caption wrappers
+		waMsg, _, _ = UnwrapMessage(waMsg)
 		return conn.Client.DownloadAny(waMsg)
 	}
 	return conn.Client.Download(downloadable)
@@ -174,15 +185,64 @@ func (conn *WhatsmeowConnection) Send(msg *whatsapp.WhatsappMessage) (whatsapp.I
 	var err error
 	messageText := msg.GetText()
 
+	// quoting a previous message if requested
+	var contextInfo *waProto.ContextInfo
+	if len(msg.InReply) > 0 {
+		contextInfo = conn.GetInReplyContextInfo(*msg)
+	}
+
+	// forwarding, marked for recipients
+	if msg.Forwarded {
+		contextInfo = &waProto.ContextInfo{
+			IsForwarded:     proto.Bool(true),
+			ForwardingScore: proto.Uint32(msg.ForwardingScore),
+		}
+	}
+
+	// mentions (@) on text and captions
+	mentions := conn.GetMentions(*msg)
+	if len(mentions) > 0 {
+		if contextInfo == nil {
+			contextInfo = &waProto.ContextInfo{}
+		}
+		contextInfo.MentionedJid = mentions
+	}
+
 	var newMessage *waProto.Message
-	if !msg.HasAttachment() {
-		internal := &waProto.ExtendedTextMessage{Text: &messageText}
+	if msg.Forwarded && msg.Content != nil {
+		newMessage, err = NewWhatsmeowForwardMessage(*msg, contextInfo)
+		if err != nil {
+			return msg, err
+		}
+	} else if msg.Type == whatsapp.ReactionMessageType {
+		newMessage = conn.NewReactionMessage(*msg)
+	} else if msg.Type == whatsapp.RevokeMessageType {
+		newMessage = conn.NewRevokeMessage(*msg)
+	} else if msg.Type == whatsapp.EditMessageType {
+		newMessage = conn.NewEditMessage(*msg)
+	} else if msg.Type == whatsapp.LocationMessageType && msg.Location != nil {
+		newMessage = NewWhatsmeowLocationMessage(msg.Location, contextInfo)
+	} else if msg.Type == whatsapp.ContactMessageType && len(msg.Contacts) > 0 {
+		newMessage = NewWhatsmeowContactMessage(msg.Contacts, contextInfo)
+	} else if msg.Type == whatsapp.PollMessageType && msg.Poll != nil {
+		newMessage, err = NewWhatsmeowPollMessage(msg.Poll, contextInfo)
+		if err != nil {
+			return msg, err
+		}
+	} else if !msg.HasAttachment() {
+		internal := &waProto.ExtendedTextMessage{Text: &messageText, ContextInfo: contextInfo}
+		if msg.Story != nil {
+			SetWhatsmeowStoryStyle(internal, msg.Story)
+		}
 		newMessage = &waProto.Message{ExtendedTextMessage: internal}
 	} else {
-		newMessage, err = conn.UploadAttachment(*msg)
+		newMessage, err = conn.UploadAttachment(*msg, contextInfo)
 		if err != nil {
 			return msg, err
 		}
+
+		// keeping the uploaded media reference, for later downloads and forwards
+		msg.Content = newMessage
 	}
 
 	// Formatting destination accordly
@@ -205,6 +265,14 @@ func (conn *WhatsmeowConnection) Send(msg *whatsapp.WhatsappMessage) (whatsapp.I
 		msg.Id = whatsmeow.GenerateMessageID()
 	}
 
+	// showing typing indicator for a while, instant replies looks robotic
+	if msg.TypingDelay > 0 {
+		err = conn.SimulateTyping(jid, *msg)
+		if err != nil {
+			return msg, err
+		}
+	}
+
 	resp, err := conn.Client.SendMessage(context.Background(), jid, msg.Id, newMessage)
 	if err != nil {
 		conn.log.Infof("send error: %s", err)
@@ -216,8 +284,179 @@ func (conn *WhatsmeowConnection) Send(msg *whatsapp.WhatsappMessage) (whatsapp.I
 	return msg, err
 }
 
+// Users to mention, including all group participants when requested
+func (conn *WhatsmeowConnection) GetMentions(msg whatsapp.WhatsappMessage) (mentions []string) {
+	mentions = msg.GetMentions()
+	if !msg.MentionAll || !msg.FromGroup() {
+		return
+	}
+
+	jid, err := types.ParseJID(msg.GetChatId())
+	if err != nil {
+		conn.log.Warnf("mention all error on get jid: %s", err)
+		return
+	}
+
+	info, err := conn.Client.GetGroupInfo(jid)
+	if err != nil {
+		conn.log.Warnf("mention all error on get group info: %s", err)
+		return
+	}
+
+	found := map[string]bool{}
+	for _, mention := range mentions {
+		found[mention] = true
+	}
+
+	for _, participant := range info.Participants {
+		wid := participant.JID.ToNonAD().String()
+		if !found[wid] {
+			found[wid] = true
+			mentions = append(mentions, wid)
+		}
+	}
+	return
+}
+
+/*
+<summary>
+	Shows typing or recording indicator, waiting a proportional time before return
+	Returns an error only if the connection was disposed while waiting
+</summary>
+*/
+func (conn *WhatsmeowConnection) SimulateTyping(jid types.JID, msg whatsapp.WhatsappMessage) (err error) {
+	switch msg.Type {
+	case whatsapp.ReactionMessageType, whatsapp.RevokeMessageType, whatsapp.EditMessageType:
+		return
+	}
+
+	// status and broadcast lists has no typing indicator
+	if jid.Server == types.BroadcastServer {
+		return
+	}
+
+	duration := msg.GetTypingDuration()
+	perr := conn.SendChatPresence(jid.String(), msg.GetChatPresence())
+	if perr != nil {
+		conn.log.Warnf("typing indicator error: %s", perr)
+		return
+	}
+
+	conn.log.Debugf("typing on: %s, for: %v", jid, duration)
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-conn.disposed:
+		err = fmt.Errorf("connection disposed while typing on: %s", jid)
+	}
+	return
+}
+
+// Get a message from the attached handlers cache
+func (conn *WhatsmeowConnection) GetCachedMessage(id string) (msg whatsapp.WhatsappMessage, err error) {
+	if conn.Handlers == nil || conn.Handlers.WAHandlers == nil {
+		err = fmt.Errorf("handlers not attached, cant get message: %s", id)
+		return
+	}
+
+	return conn.Handlers.WAHandlers.GetMessage(id)
+}
+
+// Builds a key that identifies a previous message, looking up cache for sender information
+func (conn *WhatsmeowConnection) GetMessageKey(chatId string, id string) *waProto.MessageKey {
+	key := &waProto.MessageKey{
+		RemoteJid: proto.String(chatId),
+		FromMe:    proto.Bool(false),
+		Id:        proto.String(id),
+	}
+
+	cached, err := conn.GetCachedMessage(id)
+	if err != nil {
+		conn.log.Debugf("message key built without cache information: %s", err)
+		return key
+	}
+
+	key.Id = proto.String(cached.Id)
+	key.FromMe = proto.Bool(cached.FromMe)
+	if !cached.FromMe && cached.Participant != nil {
+		key.Participant = proto.String(cached.Participant.ID)
+	}
+	return key
+}
+
+// Builds a reaction to a previous message, an empty text removes the reaction
+func (conn *WhatsmeowConnection) NewReactionMessage(msg whatsapp.WhatsappMessage) *waProto.Message {
+	internal := &waProto.ReactionMessage{
+		Key:               conn.GetMessageKey(msg.GetChatId(), msg.InReply),
+		Text:              proto.String(msg.GetText()),
+		SenderTimestampMs: proto.Int64(time.Now().UnixMilli()),
+	}
+	return &waProto.Message{ReactionMessage: internal}
+}
+
+// Builds a delete for everyone of a previous message
+func (conn *WhatsmeowConnection) NewRevokeMessage(msg whatsapp.WhatsappMessage) *waProto.Message {
+	internal := &waProto.ProtocolMessage{
+		Type: waProto.ProtocolMessage_REVOKE.Enum(),
+		Key:  conn.GetMessageKey(msg.GetChatId(), msg.InReply),
+	}
+	return &waProto.Message{ProtocolMessage: internal}
+}
+
+// Builds a text replacement of a previous message
+func (conn *WhatsmeowConnection) NewEditMessage(msg whatsapp.WhatsappMessage) *waProto.Message {
+	internal := &waProto.ProtocolMessage{
+		Type:          waProto.ProtocolMessage_MESSAGE_EDIT.Enum(),
+		Key:           conn.GetMessageKey(msg.GetChatId(), msg.InReply),
+		EditedMessage: &waProto.Message{Conversation: proto.String(msg.GetText())},
+		TimestampMs:   proto.Int64(time.Now().UnixMilli()),
+	}
+
+	// same wrapper used by official clients
+	wrapper := &waProto.FutureProofMessage{Message: &waProto.Message{ProtocolMessage: internal}}
+	return &waProto.Message{EditedMessage: wrapper}
+}
+
+// Builds a context info that quotes a cached message, used on replies
+func (conn *WhatsmeowConnection) GetInReplyContextInfo(msg whatsapp.WhatsappMessage) *waProto.ContextInfo {
+	info := &waProto.ContextInfo{StanzaId: proto.String(msg.InReply)}
+
+	quoted, err := conn.GetCachedMessage(msg.InReply)
+	if err != nil {
+		conn.log.Warnf("message to reply not found, sending without quoted content: %s", err)
+		return info
+	}
+
+	// who sent the quoted message
+	var participant string
+	if quoted.FromMe {
+		if conn.Client.Store.ID != nil {
+			participant = conn.Client.Store.ID.ToNonAD().String()
+		}
+	} else if quoted.Participant != nil {
+		participant = quoted.Participant.ID
+	} else {
+		participant = quoted.Chat.ID
+	}
+
+	if len(participant) > 0 {
+		info.Participant = proto.String(participant)
+	}
+
+	info.StanzaId = proto.String(quoted.Id)
+	info.QuotedMessage = ToWhatsmeowQuotedMessage(quoted)
+	return info
+}
+
 // func (cli *Client) Upload(ctx context.Context, plaintext []byte, appInfo MediaType) (resp UploadResponse, err error)
-func (conn *WhatsmeowConnection) UploadAttachment(msg whatsapp.WhatsappMessage) (result *waProto.Message, err error) {
+func (conn *WhatsmeowConnection) UploadAttachment(msg whatsapp.WhatsappMessage, info *waProto.ContextInfo) (result *waProto.Message, err error) {
+
+	if msg.Attachment.GetContent() == nil {
+		err = fmt.Errorf("null or empty content")
+		return
+	}
 
 	content := *msg.Attachment.GetContent()
 	if len(content) == 0 {
@@ -226,12 +465,21 @@ func (conn *WhatsmeowConnection) UploadAttachment(msg whatsapp.WhatsappMessage)
 	}
 
 	mediaType := GetMediaTypeFromString(msg.Attachment.Mimetype)
+
+	// previews for recipients before downloading
+	if mediaType != whatsmeow.MediaAudio && len(msg.Attachment.JpegThumbnail) == 0 {
+		err = whatsapp.GenerateMediaInfo(msg.Attachment)
+		if err != nil {
+			conn.log.Warnf("media info not generated, sending without preview: %s", err)
+		}
+	}
+
 	response, err := conn.Client.Upload(context.Background(), content, mediaType)
 	if err != nil {
 		return
 	}
 
-	result = NewWhatsmeowMessageAttachment(response, msg.Attachment, mediaType)
+	result = NewWhatsmeowMessageAttachment(response, msg.Attachment, mediaType, info)
 	return
 }
 
@@ -277,6 +525,90 @@ func (conn *WhatsmeowConnection) GetInvite(groupId string) (link string, err err
 	return
 }
 
+func (conn *WhatsmeowConnection) SendChatPresence(chatId string, presence whatsapp.WhatsappChatPresence) (err error) {
+	formatedDestination, err := whatsapp.FormatEndpoint(chatId)
+	if err != nil {
+		return
+	}
+
+	jid, err := types.ParseJID(formatedDestination)
+	if err != nil {
+		return
+	}
+
+	switch presence {
+	case whatsapp.ChatPresenceComposing:
+		return conn.Client.SendChatPresence(jid, types.ChatPresenceComposing, types.ChatPresenceMediaText)
+	case whatsapp.ChatPresenceRecording:
+		return conn.Client.SendChatPresence(jid, types.ChatPresenceComposing, types.ChatPresenceMediaAudio)
+	default:
+		return conn.Client.SendChatPresence(jid, types.ChatPresencePaused, types.ChatPresenceMediaText)
+	}
+}
+
+func (conn *WhatsmeowConnection) SendPresence(available bool) error {
+	if available {
+		return conn.Client.SendPresence(types.PresenceAvailable)
+	}
+	return conn.Client.SendPresence(types.PresenceUnavailable)
+}
+
+func (conn *WhatsmeowConnection) MarkRead(chatId string, senderId string, ids []string) (err error) {
+	chat, err := types.ParseJID(chatId)
+	if err != nil {
+		return
+	}
+
+	var sender types.JID
+	if len(senderId) > 0 {
+		sender, err = types.ParseJID(senderId)
+		if err != nil {
+			return
+		}
+	}
+
+	return conn.Client.MarkRead(ids, time.Now(), chat, sender)
+}
+
+// Decline an incoming call, whatsmeow does not expose it yet, so sending the raw node
+func (conn *WhatsmeowConnection) RejectCall(from string, callId string) (err error) {
+	caller, err := types.ParseJID(from)
+	if err != nil {
+		return
+	}
+
+	if conn.Client.Store.ID == nil {
+		return fmt.Errorf("not logged in")
+	}
+
+	own := conn.Client.Store.ID.ToNonAD()
+	caller = caller.ToNonAD()
+	return conn.Client.DangerousInternals().SendNode(waBinary.Node{
+		Tag:   "call",
+		Attrs: waBinary.Attrs{"id": whatsmeow.GenerateMessageID(), "from": own, "to": caller},
+		Content: []waBinary.Node{{
+			Tag:   "reject",
+			Attrs: waBinary.Attrs{"call-id": callId, "call-creator": caller, "count": "0"},
+		}},
+	})
+}
+
+func (conn *WhatsmeowConnection) IsOnWhatsApp(phone string) (registered bool, err error) {
+	phone = strings.TrimPrefix(strings.Split(phone, "@")[0], "+")
+	responses, err := conn.Client.IsOnWhatsApp([]string{"+" + phone})
+	if err != nil {
+		return
+	}
+
+	for _, response := range responses {
+		if response.IsIn {
+			registered = true
+			return
+		}
+	}
+	return
+}
+
 func (conn *WhatsmeowConnection) GetWhatsAppQRChannel(result chan<- string) (err error) {
 
 	// No ID stored, new login
@@ -324,6 +656,12 @@ func (conn *WhatsmeowConnection) EnsureHandlers() error {
 </summary>
 */
 func (conn *WhatsmeowConnection) Dispose() {
+	conn.disposing.Do(func() {
+		if conn.disposed != nil {
+			close(conn.disposed)
+		}
+	})
+
 	if conn.logger != nil {
 		conn.logger.Warnf("disposing connection ...")
 		conn.logger = nil
@@ -1,7 +1,13 @@
 package whatsmeow
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+
+	whatsmeow "go.mau.fi/whatsmeow"
 )
 
 type WhatsmeowStoreNotFoundException struct {
@@ -15,3 +21,34 @@ func (e *WhatsmeowStoreNotFoundException) Error() string {
 func (e *WhatsmeowStoreNotFoundException) Unauthorized() bool {
 	return true
 }
+
+/*
+<summary>
+	Indicates that an error comes from connection or timeout problems
+	Those may succeed on a new attempt, others (invalid chat id, malformed content, etc) will not
+</summary>
+*/
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	transients := []error{
+		whatsmeow.ErrNotConnected,
+		whatsmeow.ErrNotLoggedIn,
+		whatsmeow.ErrIQTimedOut,
+		whatsmeow.ErrMessageTimedOut,
+		context.DeadlineExceeded,
+		io.EOF,
+		io.ErrUnexpectedEOF,
+	}
+
+	for _, transient := range transients {
+		if errors.Is(err, transient) {
+			return true
+		}
+	}
+
+	var netError net.Error
+	return errors.As(err, &netError)
+}
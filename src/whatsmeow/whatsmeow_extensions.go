@@ -1,7 +1,11 @@
 package whatsmeow
 
 import (
+	"bytes"
 	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/jpeg"
 
 	_ "github.com/mattn/go-sqlite3"
 	"google.golang.org/protobuf/proto"
@@ -52,7 +56,7 @@ func ToWhatsmeowMessage(source whatsapp.IWhatsappMessage) (msg *waProto.Message,
 	return
 }
 
-func NewWhatsmeowMessageAttachment(response whatsmeow.UploadResponse, attach *whatsapp.WhatsappAttachment, media whatsmeow.MediaType) (msg *waProto.Message) {
+func NewWhatsmeowMessageAttachment(response whatsmeow.UploadResponse, attach *whatsapp.WhatsappAttachment, media whatsmeow.MediaType, info *waProto.ContextInfo) (msg *waProto.Message) {
 	switch media {
 	case whatsmeow.MediaImage:
 		msg = &waProto.Message{ImageMessage: &waProto.ImageMessage{
@@ -65,6 +69,12 @@ func NewWhatsmeowMessageAttachment(response whatsmeow.UploadResponse, attach *wh
 
 			Mimetype: proto.String(attach.Mimetype),
 			Caption:  proto.String(attach.FileName),
+
+			JpegThumbnail: attach.GetThumbnail(),
+			Width:         GetOptionalUint32(attach.Width),
+			Height:        GetOptionalUint32(attach.Height),
+
+			ContextInfo: info,
 		},
 		}
 		return
@@ -78,8 +88,13 @@ func NewWhatsmeowMessageAttachment(response whatsmeow.UploadResponse, attach *wh
 			FileLength:    proto.Uint64(response.FileLength),
 
 			Mimetype: proto.String(attach.Mimetype),
-			Ptt:      proto.Bool(attach.Mimetype == "audio/ogg"),
+			Ptt:      proto.Bool(attach.Ptt),
+			Waveform: attach.Waveform,
+
+			ContextInfo: info,
 		}
+		internal.Seconds = GetOptionalUint32(attach.Seconds)
+
 		msg = &waProto.Message{AudioMessage: internal}
 		return
 	case whatsmeow.MediaVideo:
@@ -93,6 +108,13 @@ func NewWhatsmeowMessageAttachment(response whatsmeow.UploadResponse, attach *wh
 
 			Mimetype: proto.String(attach.Mimetype),
 			Caption:  proto.String(attach.FileName),
+
+			JpegThumbnail: attach.GetThumbnail(),
+			Width:         GetOptionalUint32(attach.Width),
+			Height:        GetOptionalUint32(attach.Height),
+			Seconds:       GetOptionalUint32(attach.Seconds),
+
+			ContextInfo: info,
 		}
 		msg = &waProto.Message{VideoMessage: internal}
 		return
@@ -107,15 +129,171 @@ func NewWhatsmeowMessageAttachment(response whatsmeow.UploadResponse, attach *wh
 
 			Mimetype: proto.String(attach.Mimetype),
 			FileName: proto.String(attach.FileName),
+
+			JpegThumbnail: attach.GetThumbnail(),
+			PageCount:     GetOptionalUint32(attach.PageCount),
+
+			ContextInfo: info,
+		}
+
+		// thumbnail dimensions, required by official clients to render the preview
+		if len(internal.JpegThumbnail) > 0 {
+			config, _, err := image.DecodeConfig(bytes.NewReader(internal.JpegThumbnail))
+			if err == nil {
+				internal.ThumbnailWidth = proto.Uint32(uint32(config.Width))
+				internal.ThumbnailHeight = proto.Uint32(uint32(config.Height))
+			}
 		}
+
 		msg = &waProto.Message{DocumentMessage: internal}
 		return
 	}
 }
 
+// Wraps the original content (full message or media sub message) into a whatsmeow message, nil if unknown
+func ToWhatsmeowContentMessage(content interface{}) *waProto.Message {
+	switch content := content.(type) {
+	case *waProto.Message:
+		return content
+	case *waProto.ImageMessage:
+		return &waProto.Message{ImageMessage: content}
+	case *waProto.StickerMessage:
+		return &waProto.Message{StickerMessage: content}
+	case *waProto.DocumentMessage:
+		return &waProto.Message{DocumentMessage: content}
+	case *waProto.AudioMessage:
+		return &waProto.Message{AudioMessage: content}
+	case *waProto.VideoMessage:
+		return &waProto.Message{VideoMessage: content}
+	case *waProto.LocationMessage:
+		return &waProto.Message{LocationMessage: content}
+	case *waProto.LiveLocationMessage:
+		return &waProto.Message{LiveLocationMessage: content}
+	case *waProto.ContactMessage:
+		return &waProto.Message{ContactMessage: content}
+	case *waProto.ExtendedTextMessage:
+		return &waProto.Message{ExtendedTextMessage: content}
+	}
+	return nil
+}
+
+// Rebuilds an original whatsmeow message from a cached one, used for quoting
+func ToWhatsmeowQuotedMessage(source whatsapp.WhatsappMessage) *waProto.Message {
+	msg := ToWhatsmeowContentMessage(source.Content)
+	if msg != nil {
+		return msg
+	}
+
+	// polls sent from this api, without the original content
+	if source.Poll != nil {
+		var options []*waProto.PollCreationMessage_Option
+		for _, option := range source.Poll.Options {
+			options = append(options, &waProto.PollCreationMessage_Option{OptionName: proto.String(option)})
+		}
+		poll := &waProto.PollCreationMessage{Name: proto.String(source.Poll.Question), Options: options}
+		return &waProto.Message{PollCreationMessage: poll}
+	}
+
+	// messages sent from this api do not have the original content
+	text := source.GetText()
+	return &waProto.Message{Conversation: proto.String(text)}
+}
+
+// Creates a native location pin message
+func NewWhatsmeowLocationMessage(source *whatsapp.WhatsappLocation, info *waProto.ContextInfo) *waProto.Message {
+	internal := &waProto.LocationMessage{
+		DegreesLatitude:  proto.Float64(source.Latitude),
+		DegreesLongitude: proto.Float64(source.Longitude),
+		ContextInfo:      info,
+	}
+
+	if len(source.Name) > 0 {
+		internal.Name = proto.String(source.Name)
+	}
+
+	if len(source.Address) > 0 {
+		internal.Address = proto.String(source.Address)
+	}
+
+	if len(source.Url) > 0 {
+		internal.Url = proto.String(source.Url)
+	}
+
+	return &waProto.Message{LocationMessage: internal}
+}
+
+// Creates a contact card message, an array message if more than one contact
+func NewWhatsmeowContactMessage(source []whatsapp.WhatsappContact, info *waProto.ContextInfo) *waProto.Message {
+	var contacts []*waProto.ContactMessage
+	for _, contact := range source {
+		contacts = append(contacts, &waProto.ContactMessage{
+			DisplayName: proto.String(contact.Name),
+			Vcard:       proto.String(contact.GetVCard()),
+		})
+	}
+
+	if len(contacts) == 1 {
+		contacts[0].ContextInfo = info
+		return &waProto.Message{ContactMessage: contacts[0]}
+	}
+
+	internal := &waProto.ContactsArrayMessage{
+		DisplayName: proto.String(fmt.Sprintf("%v contacts", len(contacts))),
+		Contacts:    contacts,
+		ContextInfo: info,
+	}
+	return &waProto.Message{ContactsArrayMessage: internal}
+}
+
+// Generates a new unique message id, same format of official clients
+func GenerateMessageId() string {
+	return whatsmeow.GenerateMessageID()
+}
+
+// Returns nil for zero values, avoiding to send empty information
+func GetOptionalUint32(value uint32) *uint32 {
+	if value == 0 {
+		return nil
+	}
+	return proto.Uint32(value)
+}
+
 func GetStringFromBytes(bytes []byte) string {
 	if bytes != nil {
 		return base64.StdEncoding.EncodeToString(bytes)
 	}
 	return ""
 }
+
+// Serializes the original message, used to download attachments later, nil if not a whatsmeow message
+// Media sub messages are wrapped back into a full message, restored as such
+func MarshalContent(content interface{}) ([]byte, error) {
+	msg := ToWhatsmeowContentMessage(content)
+	if msg == nil {
+		return nil, nil
+	}
+	return proto.Marshal(msg)
+}
+
+// Restores the original message from its serialized form
+func UnmarshalContent(data []byte) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	msg := &waProto.Message{}
+	err := proto.Unmarshal(data, msg)
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Serialized size of the original message, zero if not a whatsmeow message
+func ContentSize(content interface{}) int {
+	msg := ToWhatsmeowContentMessage(content)
+	if msg == nil {
+		return 0
+	}
+	return proto.Size(msg)
+}
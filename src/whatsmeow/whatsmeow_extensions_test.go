@@ -0,0 +1,95 @@
+package whatsmeow
+
+import (
+	"testing"
+
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMarshalContentRoundTrip(t *testing.T) {
+	cases := []struct {
+		name    string
+		content interface{}
+		check   func(*waProto.Message) bool
+	}{
+		{
+			name:    "full message",
+			content: &waProto.Message{Conversation: proto.String("hello")},
+			check:   func(msg *waProto.Message) bool { return msg.GetConversation() == "hello" },
+		},
+		{
+			name:    "image",
+			content: &waProto.ImageMessage{Url: proto.String("https://mmg/image"), MediaKey: []byte{1, 2, 3}},
+			check: func(msg *waProto.Message) bool {
+				return msg.GetImageMessage().GetUrl() == "https://mmg/image" && len(msg.GetImageMessage().GetMediaKey()) == 3
+			},
+		},
+		{
+			name:    "video",
+			content: &waProto.VideoMessage{Url: proto.String("https://mmg/video")},
+			check:   func(msg *waProto.Message) bool { return msg.GetVideoMessage().GetUrl() == "https://mmg/video" },
+		},
+		{
+			name:    "audio",
+			content: &waProto.AudioMessage{Url: proto.String("https://mmg/audio"), Ptt: proto.Bool(true)},
+			check:   func(msg *waProto.Message) bool { return msg.GetAudioMessage().GetPtt() },
+		},
+		{
+			name:    "document",
+			content: &waProto.DocumentMessage{FileName: proto.String("report.pdf")},
+			check:   func(msg *waProto.Message) bool { return msg.GetDocumentMessage().GetFileName() == "report.pdf" },
+		},
+		{
+			name:    "sticker",
+			content: &waProto.StickerMessage{Url: proto.String("https://mmg/sticker")},
+			check:   func(msg *waProto.Message) bool { return msg.GetStickerMessage().GetUrl() == "https://mmg/sticker" },
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := MarshalContent(c.content)
+			if err != nil {
+				t.Fatalf("marshal error: %s", err)
+			}
+
+			if len(data) == 0 {
+				t.Fatalf("content not serialized")
+			}
+
+			restored, err := UnmarshalContent(data)
+			if err != nil {
+				t.Fatalf("unmarshal error: %s", err)
+			}
+
+			msg, ok := restored.(*waProto.Message)
+			if !ok {
+				t.Fatalf("restored content is not a message: %T", restored)
+			}
+
+			if !c.check(msg) {
+				t.Errorf("restored content differs: %v", msg)
+			}
+		})
+	}
+}
+
+func TestMarshalContentUnknown(t *testing.T) {
+	cases := []struct {
+		name    string
+		content interface{}
+	}{
+		{name: "nil", content: nil},
+		{name: "string", content: "not a whatsmeow message"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			data, err := MarshalContent(c.content)
+			if err != nil || data != nil {
+				t.Errorf("expected nil content without error, got: %v, %v", data, err)
+			}
+		})
+	}
+}
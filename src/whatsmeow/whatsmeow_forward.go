@@ -0,0 +1,52 @@
+package whatsmeow
+
+import (
+	"fmt"
+
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+/*
+<summary>
+	Copies the original content of a cached message, replacing its context with the forwarded one
+	Media keeps the same uploaded file, recipients download it without a new upload
+</summary>
+*/
+func NewWhatsmeowForwardMessage(source whatsapp.WhatsappMessage, info *waProto.ContextInfo) (msg *waProto.Message, err error) {
+	original := ToWhatsmeowQuotedMessage(source)
+	msg = proto.Clone(original).(*waProto.Message)
+
+	switch {
+	case len(msg.GetConversation()) > 0:
+		msg = &waProto.Message{ExtendedTextMessage: &waProto.ExtendedTextMessage{Text: msg.Conversation, ContextInfo: info}}
+	case msg.ExtendedTextMessage != nil:
+		msg.ExtendedTextMessage.ContextInfo = info
+	case msg.ImageMessage != nil:
+		msg.ImageMessage.ContextInfo = info
+	case msg.VideoMessage != nil:
+		msg.VideoMessage.ContextInfo = info
+	case msg.AudioMessage != nil:
+		msg.AudioMessage.ContextInfo = info
+	case msg.DocumentMessage != nil:
+		msg.DocumentMessage.ContextInfo = info
+	case msg.StickerMessage != nil:
+		msg.StickerMessage.ContextInfo = info
+	case msg.LocationMessage != nil:
+		msg.LocationMessage.ContextInfo = info
+	case msg.LiveLocationMessage != nil:
+		msg.LiveLocationMessage.ContextInfo = info
+	case msg.ContactMessage != nil:
+		msg.ContactMessage.ContextInfo = info
+	case msg.ContactsArrayMessage != nil:
+		msg.ContactsArrayMessage.ContextInfo = info
+	default:
+		err = fmt.Errorf("message type cant be forwarded: %s", source.Type)
+		return
+	}
+
+	// secrets and other metadata of the original message are not forwarded
+	msg.MessageContextInfo = nil
+	return
+}
@@ -3,10 +3,12 @@ package whatsmeow
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
 	whatsmeow "go.mau.fi/whatsmeow"
+	types "go.mau.fi/whatsmeow/types"
 	"go.mau.fi/whatsmeow/types/events"
 )
 
@@ -56,6 +58,42 @@ func (handler *WhatsmeowHandlers) EventsHandler(evt interface{}) {
 		go handler.Message(*v)
 		return
 
+	case *events.Receipt:
+		go handler.Receipt(*v)
+		return
+
+	case *events.HistorySync:
+		go handler.HistorySync(*v)
+		return
+
+	case *events.GroupInfo:
+		go handler.GroupInfo(*v)
+		return
+
+	case *events.JoinedGroup:
+		go handler.JoinedGroup(*v)
+		return
+
+	case *events.Picture:
+		go handler.Picture(*v)
+		return
+
+	case *events.CallOffer:
+		go handler.CallOffer(*v)
+		return
+
+	case *events.CallOfferNotice:
+		go handler.CallOfferNotice(*v)
+		return
+
+	case *events.CallAccept:
+		go handler.Call(v.BasicCallMeta, whatsapp.CallEventAccept, "")
+		return
+
+	case *events.CallTerminate:
+		go handler.Call(v.BasicCallMeta, whatsapp.CallEventTerminate, v.Reason)
+		return
+
 	case *events.Connected:
 		// zerando contador de tentativas de reconexão
 		// importante para zerar o tempo entre tentativas em caso de erro
@@ -69,10 +107,10 @@ func (handler *WhatsmeowHandlers) EventsHandler(evt interface{}) {
 	case
 		*events.AppState,
 		*events.AppStateSyncComplete,
+		*events.CallRelayLatency,
 		*events.Contact,
 		*events.DeleteChat,
 		*events.DeleteForMe,
-		*events.HistorySync,
 		*events.MarkChatAsRead,
 		*events.Mute,
 		*events.OfflineSyncCompleted,
@@ -81,8 +119,7 @@ func (handler *WhatsmeowHandlers) EventsHandler(evt interface{}) {
 		*events.Pin,
 		*events.PushName,
 		*events.PushNameSetting,
-		*events.QR,
-		*events.Receipt:
+		*events.QR:
 		return // ignoring not implemented yet
 
 	default:
@@ -101,28 +138,50 @@ func (handler *WhatsmeowHandlers) Message(evt events.Message) {
 		return
 	}
 
-	message := &whatsapp.WhatsappMessage{Content: evt.Message}
+	message := handler.CreateMessage(evt)
+	if evt.Info.IsGroup {
+		gInfo, _ := handler.Client.GetGroupInfo(evt.Info.Chat)
+		if gInfo != nil {
+			message.Chat.Title = gInfo.Name
+		}
+	}
+
+	if handler.WAHandlers != nil {
+
+		// following to internal handlers
+		go handler.WAHandlers.Message(message)
+	}
+}
+
+// Converts a whatsmeow message event, without looking up group information
+func (handler *WhatsmeowHandlers) CreateMessage(evt events.Message) (message *whatsapp.WhatsappMessage) {
+	message = &whatsapp.WhatsappMessage{Content: evt.Message}
 
 	// basic information
 	message.Id = evt.Info.ID
 	message.Timestamp = evt.Info.Timestamp
 	message.FromMe = evt.Info.IsFromMe
 
+	// already unwrapped by whatsmeow
+	message.Ephemeral = evt.IsEphemeral
+	message.ViewOnce = evt.IsViewOnce
+
 	message.Chat = whatsapp.WhatsappChat{}
 	chatID := fmt.Sprint(evt.Info.Chat.User, "@", evt.Info.Chat.Server)
 	message.Chat.ID = chatID
 
 	if evt.Info.IsGroup {
-		gInfo, _ := handler.Client.GetGroupInfo(evt.Info.Chat)
-		if gInfo != nil {
-			message.Chat.Title = gInfo.Name
-		}
-
 		message.Participant = &whatsapp.WhatsappEndpoint{}
 
 		participantID := fmt.Sprint(evt.Info.Sender.User, "@", evt.Info.Sender.Server)
 		message.Participant.ID = participantID
 		message.Participant.Title = evt.Info.PushName
+	} else if evt.Info.Chat == types.StatusBroadcastJID {
+
+		// who posted this status (story)
+		message.Participant = &whatsapp.WhatsappEndpoint{}
+		message.Participant.ID = fmt.Sprint(evt.Info.Sender.User, "@", evt.Info.Sender.Server)
+		message.Participant.Title = evt.Info.PushName
 	} else if !message.FromMe {
 		message.Chat.Title = evt.Info.PushName
 	}
@@ -132,11 +191,271 @@ func (handler *WhatsmeowHandlers) Message(evt events.Message) {
 	if message.Type == whatsapp.UnknownMessageType {
 		HandleUnknownMessage(handler.log, evt)
 	}
+	return
+}
 
-	if handler.WAHandlers != nil {
+//endregion
+//region EVENT HISTORY SYNC
 
-		// following to internal handlers
-		go handler.WAHandlers.Message(message)
+// Past conversations sent after pairing, flagged as historical messages
+func (handler *WhatsmeowHandlers) HistorySync(evt events.HistorySync) {
+	if handler.WAHandlers == nil || evt.Data == nil {
+		return
+	}
+
+	handler.log.Infof("history sync of type: %s, conversations: %v, progress: %v", evt.Data.GetSyncType(), len(evt.Data.GetConversations()), evt.Data.GetProgress())
+
+	var messages []*whatsapp.WhatsappMessage
+	for _, conversation := range evt.Data.GetConversations() {
+		chatJID, err := types.ParseJID(conversation.GetId())
+		if err != nil {
+			handler.log.Warnf("history sync with invalid chat id: %s, %s", conversation.GetId(), err)
+			continue
+		}
+
+		for _, item := range conversation.GetMessages() {
+			parsed, err := handler.Client.ParseWebMessage(chatJID, item.GetMessage())
+			if err != nil || parsed.Message == nil {
+				handler.log.Debugf("history sync message ignored on: %s, %v", chatJID, err)
+				continue
+			}
+
+			message := handler.CreateMessage(*parsed)
+			if parsed.Info.IsGroup {
+				message.Chat.Title = conversation.GetName()
+			}
+
+			message.Historical = true
+			messages = append(messages, message)
+		}
+	}
+
+	if len(messages) > 0 {
+		handler.WAHandlers.History(messages)
+	}
+}
+
+//endregion
+//region EVENT RECEIPT
+
+// Delivery and read receipts, one status event for each message
+func (handler *WhatsmeowHandlers) Receipt(evt events.Receipt) {
+	var status whatsapp.WhatsappMessageStatus
+	switch evt.Type {
+	case events.ReceiptTypeDelivered:
+		status = whatsapp.MessageStatusDelivered
+	case events.ReceiptTypeRead:
+		status = whatsapp.MessageStatusRead
+	case events.ReceiptTypePlayed:
+		status = whatsapp.MessageStatusPlayed
+	default:
+		handler.log.Tracef("ignoring receipt of type: %s", evt.Type)
+		return
+	}
+
+	if handler.WAHandlers == nil {
+		return
+	}
+
+	chatID := fmt.Sprint(evt.Chat.User, "@", evt.Chat.Server)
+	for _, id := range evt.MessageIDs {
+		receipt := &whatsapp.WhatsappReceipt{
+			Id:        id,
+			ChatId:    chatID,
+			Status:    status,
+			Timestamp: evt.Timestamp,
+		}
+
+		if evt.IsGroup {
+			receipt.Participant = fmt.Sprint(evt.Sender.User, "@", evt.Sender.Server)
+		}
+
+		handler.WAHandlers.Receipt(receipt)
+	}
+}
+
+//endregion
+//region EVENT GROUPS
+
+// Participants and metadata changes, splitted in one event per kind of change
+func (handler *WhatsmeowHandlers) GroupInfo(evt events.GroupInfo) {
+	if handler.WAHandlers == nil {
+		return
+	}
+
+	chatID := fmt.Sprint(evt.JID.User, "@", evt.JID.Server)
+	create := func(kind whatsapp.WhatsappGroupEventType) *whatsapp.WhatsappGroupEvent {
+		event := &whatsapp.WhatsappGroupEvent{
+			ChatId:    chatID,
+			Type:      kind,
+			Timestamp: evt.Timestamp,
+		}
+
+		if evt.Sender != nil {
+			event.Author = fmt.Sprint(evt.Sender.User, "@", evt.Sender.Server)
+		}
+		return event
+	}
+
+	participants := func(kind whatsapp.WhatsappGroupEventType, jids []types.JID) {
+		if len(jids) == 0 {
+			return
+		}
+
+		event := create(kind)
+		for _, jid := range jids {
+			event.Participants = append(event.Participants, fmt.Sprint(jid.User, "@", jid.Server))
+		}
+
+		if kind == whatsapp.GroupEventJoin {
+			event.Reason = evt.JoinReason
+		}
+		handler.WAHandlers.GroupEvent(event)
+	}
+
+	participants(whatsapp.GroupEventJoin, evt.Join)
+	participants(whatsapp.GroupEventLeave, evt.Leave)
+	participants(whatsapp.GroupEventPromote, evt.Promote)
+	participants(whatsapp.GroupEventDemote, evt.Demote)
+
+	// this bot is not a participant anymore
+	if handler.IsOwnJID(evt.Leave...) {
+		handler.WAHandlers.GroupEvent(create(whatsapp.GroupEventRemoved))
+	}
+
+	if evt.Name != nil {
+		event := create(whatsapp.GroupEventSubject)
+		event.Value = evt.Name.Name
+		handler.WAHandlers.GroupEvent(event)
+	}
+
+	if evt.Topic != nil {
+		event := create(whatsapp.GroupEventDescription)
+		event.Value = evt.Topic.Topic
+		handler.WAHandlers.GroupEvent(event)
+	}
+
+	if evt.Locked != nil {
+		event := create(whatsapp.GroupEventLocked)
+		event.Value = fmt.Sprint(evt.Locked.IsLocked)
+		handler.WAHandlers.GroupEvent(event)
+	}
+
+	if evt.Announce != nil {
+		event := create(whatsapp.GroupEventAnnounce)
+		event.Value = fmt.Sprint(evt.Announce.IsAnnounce)
+		handler.WAHandlers.GroupEvent(event)
+	}
+
+	if evt.Ephemeral != nil {
+		event := create(whatsapp.GroupEventEphemeral)
+		event.Value = fmt.Sprint(evt.Ephemeral.DisappearingTimer)
+		handler.WAHandlers.GroupEvent(event)
+	}
+
+	if evt.NewInviteLink != nil {
+		event := create(whatsapp.GroupEventInviteLink)
+		event.Value = *evt.NewInviteLink
+		handler.WAHandlers.GroupEvent(event)
+	}
+}
+
+// This bot was added to a group or joined via invite link
+func (handler *WhatsmeowHandlers) JoinedGroup(evt events.JoinedGroup) {
+	if handler.WAHandlers == nil {
+		return
+	}
+
+	event := &whatsapp.WhatsappGroupEvent{
+		ChatId:    fmt.Sprint(evt.JID.User, "@", evt.JID.Server),
+		Type:      whatsapp.GroupEventAdded,
+		Value:     evt.Name,
+		Reason:    evt.Reason,
+		Timestamp: time.Now().UTC(),
+	}
+
+	handler.WAHandlers.GroupEvent(event)
+}
+
+// Group picture changes, user pictures are ignored
+func (handler *WhatsmeowHandlers) Picture(evt events.Picture) {
+	if handler.WAHandlers == nil || evt.JID.Server != types.GroupServer {
+		return
+	}
+
+	event := &whatsapp.WhatsappGroupEvent{
+		ChatId:    fmt.Sprint(evt.JID.User, "@", evt.JID.Server),
+		Type:      whatsapp.GroupEventIcon,
+		Author:    fmt.Sprint(evt.Author.User, "@", evt.Author.Server),
+		Timestamp: evt.Timestamp,
+	}
+
+	if !evt.Remove {
+		event.Value = evt.PictureID
+	}
+
+	handler.WAHandlers.GroupEvent(event)
+}
+
+// Indicates that any of these jids is the connected account
+func (handler *WhatsmeowHandlers) IsOwnJID(jids ...types.JID) bool {
+	if handler.Client == nil || handler.Client.Store == nil || handler.Client.Store.ID == nil {
+		return false
+	}
+
+	own := handler.Client.Store.ID.User
+	for _, jid := range jids {
+		if jid.User == own {
+			return true
+		}
+	}
+	return false
+}
+
+//endregion
+//region EVENT CALLS
+
+// Direct call, media type comes from offer content
+func (handler *WhatsmeowHandlers) CallOffer(evt events.CallOffer) {
+	call := handler.CreateCall(evt.BasicCallMeta, whatsapp.CallEventOffer)
+	if evt.Data != nil {
+		_, call.Video = evt.Data.GetOptionalChildByTag("video")
+	}
+	handler.FollowCall(call)
+}
+
+// Group calls, notified instead of offered
+func (handler *WhatsmeowHandlers) CallOfferNotice(evt events.CallOfferNotice) {
+	call := handler.CreateCall(evt.BasicCallMeta, whatsapp.CallEventOffer)
+	call.Video = evt.Media == "video"
+	call.Group = evt.Type == "group"
+	handler.FollowCall(call)
+}
+
+func (handler *WhatsmeowHandlers) Call(meta types.BasicCallMeta, kind whatsapp.WhatsappCallEventType, reason string) {
+	call := handler.CreateCall(meta, kind)
+	call.Reason = reason
+	handler.FollowCall(call)
+}
+
+func (handler *WhatsmeowHandlers) CreateCall(meta types.BasicCallMeta, kind whatsapp.WhatsappCallEventType) *whatsapp.WhatsappCall {
+	call := &whatsapp.WhatsappCall{
+		Id:        meta.CallID,
+		Type:      kind,
+		ChatId:    fmt.Sprint(meta.From.User, "@", meta.From.Server),
+		Group:     meta.From.Server == types.GroupServer,
+		Timestamp: meta.Timestamp,
+	}
+
+	if len(meta.CallCreator.User) > 0 {
+		call.Creator = fmt.Sprint(meta.CallCreator.User, "@", meta.CallCreator.Server)
+	}
+	return call
+}
+
+func (handler *WhatsmeowHandlers) FollowCall(call *whatsapp.WhatsappCall) {
+	if handler.WAHandlers != nil {
+		handler.WAHandlers.Call(call)
 	}
 }
 
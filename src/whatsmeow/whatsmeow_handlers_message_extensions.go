@@ -14,6 +14,16 @@ import (
 )
 
 func HandleKnowingMessages(handler *WhatsmeowHandlers, out *whatsapp.WhatsappMessage, in *proto.Message) {
+	unwrapped, ephemeral, viewOnce := UnwrapMessage(in)
+	if unwrapped != in {
+		out.Content = unwrapped
+		out.Ephemeral = out.Ephemeral || ephemeral
+		out.ViewOnce = out.ViewOnce || viewOnce
+		in = unwrapped
+	}
+
+	HandleContextInfo(handler.log, out, GetContextInfo(in))
+
 	if in.ImageMessage != nil {
 		HandleImageMessage(handler.log, out, in.ImageMessage)
 	} else if in.StickerMessage != nil {
@@ -32,13 +42,144 @@ func HandleKnowingMessages(handler *WhatsmeowHandlers, out *whatsapp.WhatsappMes
 		HandleLiveLocationMessage(handler.log, out, in.LiveLocationMessage)
 	} else if in.ContactMessage != nil {
 		HandleContactMessage(handler.log, out, in.ContactMessage)
-	} else if in.ProtocolMessage != nil || in.SenderKeyDistributionMessage != nil {
+	} else if in.ReactionMessage != nil {
+		HandleReactionMessage(handler.log, out, in.ReactionMessage)
+	} else if in.PollCreationMessage != nil {
+		HandlePollCreationMessage(handler.log, out, in)
+	} else if in.PollUpdateMessage != nil {
+		HandlePollUpdateMessage(handler, out, in.PollUpdateMessage)
+	} else if in.ProtocolMessage != nil {
+		HandleProtocolMessage(handler.log, out, in.ProtocolMessage)
+	} else if in.SenderKeyDistributionMessage != nil {
 		out.Type = whatsapp.DiscardMessageType
 	} else if len(in.GetConversation()) > 0 {
 		HandleTextMessage(handler.log, out, in)
 	}
 }
 
+/*
+<summary>
+	Messages that only contains another one, unwrapped recursively
+	Informs if any of the wrappers was ephemeral or view once
+</summary>
+*/
+func UnwrapMessage(in *proto.Message) (out *proto.Message, ephemeral bool, viewOnce bool) {
+	out = in
+	for out != nil {
+		if inner := out.GetDeviceSentMessage().GetMessage(); inner != nil {
+			out = inner
+		} else if inner := out.GetEphemeralMessage().GetMessage(); inner != nil {
+			ephemeral = true
+			out = inner
+		} else if inner := out.GetViewOnceMessage().GetMessage(); inner != nil {
+			viewOnce = true
+			out = inner
+		} else if inner := out.GetViewOnceMessageV2().GetMessage(); inner != nil {
+			viewOnce = true
+			out = inner
+		} else if inner := out.GetDocumentWithCaptionMessage().GetMessage(); inner != nil {
+			out = inner
+		} else {
+			break
+		}
+	}
+	return
+}
+
+// Context of the message content, quoted message, mentions, expiration, etc
+func GetContextInfo(in *proto.Message) *proto.ContextInfo {
+	contents := []interface {
+		GetContextInfo() *proto.ContextInfo
+	}{
+		in.GetExtendedTextMessage(),
+		in.GetImageMessage(),
+		in.GetVideoMessage(),
+		in.GetAudioMessage(),
+		in.GetDocumentMessage(),
+		in.GetStickerMessage(),
+		in.GetLocationMessage(),
+		in.GetLiveLocationMessage(),
+		in.GetContactMessage(),
+		in.GetPollCreationMessage(),
+	}
+
+	for _, content := range contents {
+		if info := content.GetContextInfo(); info != nil {
+			return info
+		}
+	}
+	return nil
+}
+
+// Quoted message, mentions, forwarding and ads, common to all message types
+func HandleContextInfo(log *log.Entry, out *whatsapp.WhatsappMessage, info *proto.ContextInfo) {
+	if info == nil {
+		return
+	}
+
+	if info.GetExpiration() > 0 {
+		out.Ephemeral = true
+		out.Expiration = info.GetExpiration()
+	}
+
+	out.ForwardingScore = info.GetForwardingScore()
+	out.IsForwarded = info.GetIsForwarded()
+
+	if len(info.GetStanzaId()) > 0 {
+		out.InReply = info.GetStanzaId()
+		out.Quoted = &whatsapp.WhatsappQuoted{
+			Id:          info.GetStanzaId(),
+			Type:        GetMessageType(info.GetQuotedMessage()),
+			Text:        GetTextFromMessage(info.GetQuotedMessage()),
+			Participant: info.GetParticipant(),
+		}
+	}
+
+	out.Mentions = info.GetMentionedJid()
+
+	if ad := info.GetExternalAdReply(); ad != nil {
+		log.Debug("Received a message from ads !")
+		out.Ads = &whatsapp.WhatsappAds{
+			Id:           ad.GetSourceId(),
+			Type:         ad.GetSourceType(),
+			Title:        ad.GetTitle(),
+			Body:         ad.GetBody(),
+			SourceUrl:    ad.GetSourceUrl(),
+			MediaUrl:     ad.GetMediaUrl(),
+			ThumbnailUrl: ad.GetThumbnailUrl(),
+		}
+	}
+}
+
+// Type of a message content, without processing it
+func GetMessageType(in *proto.Message) whatsapp.WhatsappMessageType {
+	in, _, _ = UnwrapMessage(in)
+	if in == nil {
+		return whatsapp.UnknownMessageType
+	}
+
+	switch {
+	case in.ImageMessage != nil, in.StickerMessage != nil:
+		return whatsapp.ImageMessageType
+	case in.DocumentMessage != nil:
+		return whatsapp.DocumentMessageType
+	case in.AudioMessage != nil:
+		return whatsapp.AudioMessageType
+	case in.VideoMessage != nil:
+		return whatsapp.VideoMessageType
+	case in.LocationMessage != nil, in.LiveLocationMessage != nil:
+		return whatsapp.LocationMessageType
+	case in.ContactMessage != nil:
+		return whatsapp.ContactMessageType
+	case in.PollCreationMessage != nil:
+		return whatsapp.PollMessageType
+	case in.ExtendedTextMessage != nil, len(in.GetConversation()) > 0:
+		return whatsapp.TextMessageType
+	default:
+		return whatsapp.UnknownMessageType
+	}
+}
+
 func HandleUnknownMessage(log *log.Entry, in interface{}) {
 	log.Info("Received an unknown message !")
 	b, err := json.Marshal(in)
@@ -64,16 +205,8 @@ func HandleExtendedTextMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in
 		out.Text = *in.Text
 	}
 
-	info := in.ContextInfo
-	if info != nil {
-		if info.ForwardingScore != nil {
-			out.ForwardingScore = *info.ForwardingScore
-		}
-
-		if info.StanzaId != nil {
-			out.InReply = *info.StanzaId
-		}
-	}
+	// styled text, usually a status (story)
+	out.Story = GetWhatsappStory(in)
 }
 
 func HandleImageMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *proto.ImageMessage) {
@@ -133,7 +266,10 @@ func HandleDocumentMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *pr
 	out.Content = in
 	out.Type = whatsapp.DocumentMessageType
 
-	if in.Title != nil {
+	// caption comes with document with caption messages, otherwise the title
+	if len(in.GetCaption()) > 0 {
+		out.Text = in.GetCaption()
+	} else if in.Title != nil {
 		out.Text = *in.Title
 	}
 
@@ -161,7 +297,9 @@ func HandleAudioMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *proto
 		Mimetype:   *in.Mimetype,
 		FileLength: *in.FileLength,
 
-		Seconds: seconds,
+		Seconds:  seconds,
+		Ptt:      in.GetPtt(),
+		Waveform: in.Waveform,
 	}
 
 	// get file extension from mime type
@@ -264,3 +402,50 @@ func HandleContactMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *pro
 
 	out.Attachment.SetContent(&content)
 }
+
+func HandleReactionMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *proto.ReactionMessage) {
+	log.Debug("Received a Reaction message !")
+	out.Type = whatsapp.ReactionMessageType
+
+	// empty text means that the reaction was removed
+	out.Text = in.GetText()
+	out.InReply = in.GetKey().GetId()
+}
+
+// Revoked and edited messages, others are discarded
+func HandleProtocolMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *proto.ProtocolMessage) {
+	switch in.GetType() {
+	case proto.ProtocolMessage_REVOKE:
+		log.Debug("Received a Revoke message !")
+		out.Type = whatsapp.RevokeMessageType
+		out.InReply = in.GetKey().GetId()
+	case proto.ProtocolMessage_MESSAGE_EDIT:
+		log.Debug("Received an Edit message !")
+		out.Type = whatsapp.EditMessageType
+		out.InReply = in.GetKey().GetId()
+		out.Text = GetTextFromMessage(in.GetEditedMessage())
+	default:
+		out.Type = whatsapp.DiscardMessageType
+	}
+}
+
+// Returns the text or caption of a message, if any
+func GetTextFromMessage(in *proto.Message) string {
+	in, _, _ = UnwrapMessage(in)
+	if in == nil {
+		return ""
+	}
+
+	if len(in.GetConversation()) > 0 {
+		return in.GetConversation()
+	} else if in.ExtendedTextMessage != nil {
+		return in.ExtendedTextMessage.GetText()
+	} else if in.ImageMessage != nil {
+		return in.ImageMessage.GetCaption()
+	} else if in.VideoMessage != nil {
+		return in.VideoMessage.GetCaption()
+	} else if in.DocumentMessage != nil {
+		return in.DocumentMessage.GetCaption()
+	}
+	return ""
+}
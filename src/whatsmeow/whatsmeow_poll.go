@@ -0,0 +1,180 @@
+package whatsmeow
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+	"go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/util/hkdfutil"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+// Modification type used to derive poll vote encryption keys
+const PollVoteModificationType = "Poll Vote"
+
+// Creates a native poll message, generating a new encryption secret if none
+func NewWhatsmeowPollMessage(source *whatsapp.WhatsappPoll, info *proto.ContextInfo) (*proto.Message, error) {
+	if len(source.Secret) == 0 {
+		secret := make([]byte, 32)
+		_, err := rand.Read(secret)
+		if err != nil {
+			return nil, err
+		}
+		source.Secret = secret
+	}
+
+	var options []*proto.PollCreationMessage_Option
+	for _, option := range source.Options {
+		options = append(options, &proto.PollCreationMessage_Option{OptionName: protobuf.String(option)})
+	}
+
+	internal := &proto.PollCreationMessage{
+		EncKey:                 source.Secret,
+		Name:                   protobuf.String(source.Question),
+		Options:                options,
+		SelectableOptionsCount: protobuf.Uint32(source.Selectable),
+		ContextInfo:            info,
+	}
+
+	msg := &proto.Message{
+		PollCreationMessage: internal,
+		MessageContextInfo:  &proto.MessageContextInfo{MessageSecret: source.Secret},
+	}
+	return msg, nil
+}
+
+// Inbound poll creation
+func HandlePollCreationMessage(log *log.Entry, out *whatsapp.WhatsappMessage, in *proto.Message) {
+	log.Debug("Received a Poll message !")
+	creation := in.PollCreationMessage
+	out.Type = whatsapp.PollMessageType
+	out.Text = creation.GetName()
+
+	poll := &whatsapp.WhatsappPoll{
+		Question:   creation.GetName(),
+		Selectable: creation.GetSelectableOptionsCount(),
+		Secret:     in.GetMessageContextInfo().GetMessageSecret(),
+	}
+
+	// older clients sends the secret inside the poll itself
+	if len(poll.Secret) == 0 {
+		poll.Secret = creation.GetEncKey()
+	}
+
+	for _, option := range creation.GetOptions() {
+		poll.Options = append(poll.Options, option.GetOptionName())
+	}
+
+	out.Poll = poll
+}
+
+// Inbound vote, decrypted with the secret of the cached poll
+func HandlePollUpdateMessage(handler *WhatsmeowHandlers, out *whatsapp.WhatsappMessage, in *proto.PollUpdateMessage) {
+	handler.log.Debug("Received a Poll Vote message !")
+	out.Type = whatsapp.PollVoteMessageType
+	out.InReply = in.GetPollCreationMessageKey().GetId()
+
+	if handler.WAHandlers == nil {
+		handler.log.Warnf("handlers not attached, cant decode vote for poll: %s", out.InReply)
+		return
+	}
+
+	cached, err := handler.WAHandlers.GetMessage(out.InReply)
+	if err != nil || cached.Poll == nil {
+		handler.log.Warnf("poll not found on cache, cant decode vote: %s", out.InReply)
+		return
+	}
+
+	ownId := ""
+	if handler.Client.Store.ID != nil {
+		ownId = handler.Client.Store.ID.ToNonAD().String()
+	}
+
+	// who created the poll
+	creator := cached.Chat.ID
+	if cached.FromMe {
+		creator = ownId
+	} else if cached.Participant != nil {
+		creator = cached.Participant.ID
+	}
+
+	// who is voting
+	voter := out.Chat.ID
+	if out.Participant != nil {
+		voter = out.Participant.ID
+	} else if out.FromMe {
+		voter = ownId
+	}
+
+	vote, err := DecryptPollVote(cached.Poll.Secret, cached.Id, creator, voter, in.GetVote())
+	if err != nil {
+		handler.log.Errorf("error on decrypting vote for poll: %s, %s", cached.Id, err)
+		return
+	}
+
+	poll := &whatsapp.WhatsappPoll{
+		Question:   cached.Poll.Question,
+		Options:    cached.Poll.Options,
+		Selectable: cached.Poll.Selectable,
+	}
+
+	// options are identified by the sha256 of its names
+	for _, selected := range vote.GetSelectedOptions() {
+		for _, option := range cached.Poll.Options {
+			hash := sha256.Sum256([]byte(option))
+			if string(hash[:]) == string(selected) {
+				poll.Votes = append(poll.Votes, option)
+				break
+			}
+		}
+	}
+
+	out.Poll = poll
+	for index, option := range poll.Votes {
+		if index > 0 {
+			out.Text += ", "
+		}
+		out.Text += option
+	}
+}
+
+// Decrypts a poll vote using the secret of the original poll message
+func DecryptPollVote(secret []byte, pollId string, creator string, voter string, vote *proto.PollEncValue) (result *proto.PollVoteMessage, err error) {
+	if len(secret) == 0 {
+		err = fmt.Errorf("poll secret missing")
+		return
+	}
+
+	if vote == nil {
+		err = fmt.Errorf("encrypted vote missing")
+		return
+	}
+
+	info := []byte(pollId + creator + voter + PollVoteModificationType)
+	key := hkdfutil.SHA256(secret, nil, info, 32)
+	additional := []byte(fmt.Sprintf("%s\x00%s", pollId, voter))
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return
+	}
+
+	plaintext, err := gcm.Open(nil, vote.GetEncIv(), vote.GetEncPayload(), additional)
+	if err != nil {
+		return
+	}
+
+	result = &proto.PollVoteMessage{}
+	err = protobuf.Unmarshal(plaintext, result)
+	return
+}
@@ -0,0 +1,105 @@
+package whatsmeow
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"go.mau.fi/whatsmeow/binary/proto"
+	"go.mau.fi/whatsmeow/util/hkdfutil"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const (
+	testPollId      = "3EB0POLL"
+	testPollCreator = "5521999990001@s.whatsapp.net"
+	testPollVoter   = "5521999990002@s.whatsapp.net"
+)
+
+var testPollSecret = bytes.Repeat([]byte{7}, 32)
+
+// Encrypts a vote as a whatsapp client would, selecting options by the sha256 of its names
+func encryptTestPollVote(t *testing.T, secret []byte, voter string, options ...string) *proto.PollEncValue {
+	t.Helper()
+
+	vote := &proto.PollVoteMessage{}
+	for _, option := range options {
+		hash := sha256.Sum256([]byte(option))
+		vote.SelectedOptions = append(vote.SelectedOptions, hash[:])
+	}
+
+	plaintext, err := protobuf.Marshal(vote)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := hkdfutil.SHA256(secret, nil, []byte(testPollId+testPollCreator+voter+PollVoteModificationType), 32)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iv := make([]byte, gcm.NonceSize())
+	additional := []byte(fmt.Sprintf("%s\x00%s", testPollId, voter))
+	return &proto.PollEncValue{EncIv: iv, EncPayload: gcm.Seal(nil, iv, plaintext, additional)}
+}
+
+func TestDecryptPollVote(t *testing.T) {
+	vote := encryptTestPollVote(t, testPollSecret, testPollVoter, "yes", "maybe")
+
+	result, err := DecryptPollVote(testPollSecret, testPollId, testPollCreator, testPollVoter, vote)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	selected := result.GetSelectedOptions()
+	if len(selected) != 2 {
+		t.Fatalf("expected 2 selected options, got: %v", len(selected))
+	}
+
+	maybe := sha256.Sum256([]byte("maybe"))
+	if !bytes.Equal(selected[1], maybe[:]) {
+		t.Errorf("second option is not the hash of maybe")
+	}
+}
+
+func TestDecryptPollVoteRemoved(t *testing.T) {
+	vote := encryptTestPollVote(t, testPollSecret, testPollVoter)
+
+	result, err := DecryptPollVote(testPollSecret, testPollId, testPollCreator, testPollVoter, vote)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(result.GetSelectedOptions()) > 0 {
+		t.Errorf("removed vote should not select options")
+	}
+}
+
+func TestDecryptPollVoteFailures(t *testing.T) {
+	vote := encryptTestPollVote(t, testPollSecret, testPollVoter, "yes")
+
+	if _, err := DecryptPollVote(testPollSecret, testPollId, testPollCreator, testPollCreator, vote); err == nil {
+		t.Error("decrypted a vote as another voter")
+	}
+
+	if _, err := DecryptPollVote(make([]byte, 32), testPollId, testPollCreator, testPollVoter, vote); err == nil {
+		t.Error("decrypted a vote with a wrong secret")
+	}
+
+	if _, err := DecryptPollVote(nil, testPollId, testPollCreator, testPollVoter, vote); err == nil {
+		t.Error("accepted a missing secret")
+	}
+
+	if _, err := DecryptPollVote(testPollSecret, testPollId, testPollCreator, testPollVoter, nil); err == nil {
+		t.Error("accepted a missing vote")
+	}
+}
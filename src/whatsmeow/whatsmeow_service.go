@@ -72,6 +72,7 @@ func (service *WhatsmeowServiceModel) CreateEmptyConnection() (conn *WhatsmeowCo
 		logger:   logger,
 		waLogger: clientLog,
 		log:      loggerEntry,
+		disposed: make(chan struct{}),
 	}
 	return
 }
@@ -99,6 +100,7 @@ func (service *WhatsmeowServiceModel) CreateConnection(wid string, logger *log.L
 		logger:   logger,
 		waLogger: clientLog,
 		log:      loggerEntry,
+		disposed: make(chan struct{}),
 	}
 	return
 }
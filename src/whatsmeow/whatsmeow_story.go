@@ -0,0 +1,43 @@
+package whatsmeow
+
+import (
+	whatsapp "github.com/sufficit/sufficit-quepasa/whatsapp"
+	waProto "go.mau.fi/whatsmeow/binary/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Applies background, text color and font of a text status
+func SetWhatsmeowStoryStyle(internal *waProto.ExtendedTextMessage, source *whatsapp.WhatsappStory) {
+	if background, err := whatsapp.ParseArgb(source.BackgroundColor); err == nil {
+		internal.BackgroundArgb = proto.Uint32(background)
+	}
+
+	if text, err := whatsapp.ParseArgb(source.TextColor); err == nil {
+		internal.TextArgb = proto.Uint32(text)
+	}
+
+	if font := source.GetFontIndex(); font >= 0 {
+		internal.Font = waProto.ExtendedTextMessage_FontType(font).Enum()
+	}
+}
+
+// Style of inbound text status, nil if not styled
+func GetWhatsappStory(in *waProto.ExtendedTextMessage) *whatsapp.WhatsappStory {
+	if in.BackgroundArgb == nil && in.TextArgb == nil && in.Font == nil {
+		return nil
+	}
+
+	story := &whatsapp.WhatsappStory{}
+	if in.BackgroundArgb != nil {
+		story.BackgroundColor = whatsapp.FormatArgb(in.GetBackgroundArgb())
+	}
+
+	if in.TextArgb != nil {
+		story.TextColor = whatsapp.FormatArgb(in.GetTextArgb())
+	}
+
+	if in.Font != nil && int(in.GetFont()) < len(whatsapp.WhatsappStoryFonts) {
+		story.Font = whatsapp.WhatsappStoryFonts[in.GetFont()]
+	}
+	return story
+}